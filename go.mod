@@ -5,6 +5,7 @@ go 1.23.8
 require (
 	github.com/gdamore/tcell/v2 v2.8.1
 	github.com/junegunn/fzf v0.64.0
+	golang.org/x/text v0.21.0
 )
 
 require (
@@ -15,5 +16,4 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/term v0.29.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
 )
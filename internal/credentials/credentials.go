@@ -0,0 +1,334 @@
+// Package credentials stores per-feed authentication for private podcast feeds,
+// and API keys for services such as podcast directories. Credentials live in
+// their own file, readable only by the user, so they never end up in
+// subscriptions.json or settings.json.
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// fileName is the credentials file inside the config directory
+const fileName = "credentials.json"
+
+// filePerm keeps the credentials file private to the user
+const filePerm = 0600
+
+// Credential holds the authentication sent with requests for one feed
+type Credential struct {
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+
+	// AllowedHosts lists extra hosts, besides the feed's own, that may receive
+	// the credential, e.g. a CDN serving the premium enclosures
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+
+	// feedHost is the host of the feed URL the credential is stored under
+	feedHost string
+}
+
+// IsEmpty reports whether the credential carries no authentication
+func (c *Credential) IsEmpty() bool {
+	return c.Username == "" && c.Password == "" && len(c.Headers) == 0
+}
+
+// Allows reports whether the credential may be sent to targetURL. Only the feed's
+// host and explicitly allowed hosts receive credentials.
+func (c *Credential) Allows(targetURL string) bool {
+	u, err := url.Parse(targetURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == c.feedHost {
+		return true
+	}
+	for _, allowed := range c.AllowedHosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply adds the credential to req
+func (c *Credential) Apply(req *http.Request) {
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// Strip removes the credential from req
+func (c *Credential) Strip(req *http.Request) {
+	req.Header.Del("Authorization")
+	for name := range c.Headers {
+		req.Header.Del(name)
+	}
+}
+
+// CheckRedirect is an http.Client redirect policy that keeps the credential only
+// while redirects stay on hosts allowed to receive it
+func (c *Credential) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	if c.Allows(req.URL.String()) {
+		c.Apply(req)
+	} else {
+		c.Strip(req)
+	}
+	return nil
+}
+
+// APIKey authenticates with a web service, such as a podcast directory
+type APIKey struct {
+	Key    string `json:"key"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Store is the set of credentials keyed by feed URL, and of API keys keyed by
+// service name
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	Feeds   map[string]*Credential `json:"feeds"`
+	APIKeys map[string]*APIKey     `json:"apiKeys,omitempty"`
+}
+
+// Load reads the credentials file from configDir. A missing file yields an empty store.
+func Load(configDir string) (*Store, error) {
+	s := &Store{
+		path:  filepath.Join(configDir, fileName),
+		Feeds: make(map[string]*Credential),
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	// Tighten permissions if the file was created or copied by hand
+	if info.Mode().Perm()&^filePerm != 0 {
+		log.Printf("Credentials file had permissions %v, restricting to %v", info.Mode().Perm(), os.FileMode(filePerm))
+		if err := os.Chmod(s.path, filePerm); err != nil {
+			log.Printf("Failed to restrict credentials file permissions: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("failed to parse %s: %w", fileName, err)
+	}
+	if s.Feeds == nil {
+		s.Feeds = make(map[string]*Credential)
+	}
+	for feedURL, cred := range s.Feeds {
+		if cred == nil {
+			delete(s.Feeds, feedURL)
+			continue
+		}
+		cred.feedHost = hostOf(feedURL)
+	}
+
+	return s, nil
+}
+
+// Save writes the credentials file, readable only by the user
+func (s *Store) Save() error {
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	// Write to a private temp file first so the credentials are never world-readable
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePerm); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, filePerm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Get returns the credential stored for feedURL, or nil
+func (s *Store) Get(feedURL string) *Credential {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Feeds[feedURL]
+}
+
+// Set stores cred for feedURL, removing the entry if cred is empty
+func (s *Store) Set(feedURL string, cred *Credential) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cred == nil || cred.IsEmpty() {
+		delete(s.Feeds, feedURL)
+		return
+	}
+	cred.feedHost = hostOf(feedURL)
+	s.Feeds[feedURL] = cred
+}
+
+// Remove deletes the credential stored for feedURL
+func (s *Store) Remove(feedURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Feeds, feedURL)
+}
+
+// Move stores the credential kept under oldURL under newURL instead, after the
+// feed moved there. The old host stays allowed, since the feed's enclosures are
+// often still served from it. A credential already stored under newURL is kept.
+// It reports whether a credential was moved.
+func (s *Store) Move(oldURL, newURL string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cred := s.Feeds[oldURL]
+	if cred == nil || oldURL == newURL || s.Feeds[newURL] != nil {
+		return false
+	}
+
+	// Copy it, as the old credential may be in use by a fetch
+	moved := *cred
+	moved.AllowedHosts = append([]string(nil), cred.AllowedHosts...)
+	moved.feedHost = hostOf(newURL)
+	if cred.feedHost != "" && !moved.Allows(oldURL) {
+		moved.AllowedHosts = append(moved.AllowedHosts, cred.feedHost)
+	}
+
+	delete(s.Feeds, oldURL)
+	s.Feeds[newURL] = &moved
+	return true
+}
+
+// APIKey returns the key stored for service, or nil
+func (s *Store) APIKey(service string) *APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.APIKeys[service]
+}
+
+// SetAPIKey stores key for service, removing the entry if key is nil or has no Key
+func (s *Store) SetAPIKey(service string, key *APIKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == nil || key.Key == "" {
+		delete(s.APIKeys, service)
+		return
+	}
+	if s.APIKeys == nil {
+		s.APIKeys = make(map[string]*APIKey)
+	}
+	s.APIKeys[service] = key
+}
+
+// Lookup returns the first credential stored under any of feedURLs that may be
+// sent to targetURL, or nil
+func (s *Store) Lookup(targetURL string, feedURLs ...string) *Credential {
+	for _, feedURL := range feedURLs {
+		if cred := s.Get(feedURL); cred != nil && cred.Allows(targetURL) {
+			return cred
+		}
+	}
+	return nil
+}
+
+// SplitUserInfo removes user:password from rawURL, returning the clean URL and
+// a basic auth credential, or nil if the URL carried none
+func SplitUserInfo(rawURL string) (string, *Credential) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL, nil
+	}
+
+	password, _ := u.User.Password()
+	cred := &Credential{
+		Username: u.User.Username(),
+		Password: password,
+	}
+	u.User = nil
+	return u.String(), cred
+}
+
+// sensitiveParams are query parameter names, or parts of names, whose values are redacted
+var sensitiveParams = []string{"token", "key", "auth", "secret", "password", "sig", "session"}
+
+// RedactURL hides passwords and secret-looking query parameters so rawURL can be logged
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		}
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		redacted := false
+		for name := range query {
+			lower := strings.ToLower(name)
+			for _, sensitive := range sensitiveParams {
+				if strings.Contains(lower, sensitive) {
+					query.Set(name, "xxxxx")
+					redacted = true
+					break
+				}
+			}
+		}
+		if redacted {
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	return u.String()
+}
+
+// SameHost reports whether two URLs are on the same host, which may receive the
+// same credentials
+func SameHost(a, b string) bool {
+	host := hostOf(a)
+	return host != "" && host == hostOf(b)
+}
+
+// hostOf returns the lower-cased host name of rawURL
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,120 @@
+// Package directory searches podcast directories for shows to subscribe to
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultUserAgent identifies the app to directory APIs, some of which require
+// one, when the Config gives none
+const defaultUserAgent = "podcast-tui"
+
+// maxResponseBytes bounds the size of a search response
+const maxResponseBytes = 10 << 20
+
+// DefaultLimit is the number of results requested when none is given
+const DefaultLimit = 50
+
+// Result is a podcast found in a directory
+type Result struct {
+	Title        string
+	Author       string
+	FeedURL      string
+	Description  string
+	EpisodeCount int // 0 if the directory doesn't report it
+	Genre        string
+	ArtworkURL   string
+}
+
+// Provider searches a podcast directory
+type Provider interface {
+	// Name returns the directory's display name
+	Name() string
+
+	// Search returns up to limit podcasts matching query
+	Search(ctx context.Context, query string, limit int) ([]Result, error)
+}
+
+// Provider names accepted by New
+const (
+	ProviderITunes       = "itunes"
+	ProviderPodcastIndex = "podcastindex"
+)
+
+// Config selects and configures a directory provider
+type Config struct {
+	// Provider is ProviderITunes or ProviderPodcastIndex
+	Provider string
+
+	// BaseURL overrides the provider's API location, e.g. for a local stand-in server
+	BaseURL string
+
+	// APIKey and APISecret authenticate with Podcast Index
+	APIKey    string
+	APISecret string
+
+	// UserAgent is sent with every request; empty uses defaultUserAgent
+	UserAgent string
+}
+
+// New returns the provider described by cfg
+func New(cfg Config) (Provider, error) {
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "", ProviderITunes:
+		p := NewITunes(cfg.BaseURL)
+		p.userAgent = userAgent
+		return p, nil
+	case ProviderPodcastIndex:
+		if cfg.APIKey == "" || cfg.APISecret == "" {
+			return nil, fmt.Errorf("podcast index requires an API key and secret")
+		}
+		p := NewPodcastIndex(cfg.BaseURL, cfg.APIKey, cfg.APISecret)
+		p.userAgent = userAgent
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown directory provider %q", cfg.Provider)
+	}
+}
+
+// httpClient is shared by the providers
+var httpClient = &http.Client{Timeout: 20 * time.Second}
+
+// getJSON performs req, identified by userAgent, and decodes the JSON response into v
+func getJSON(req *http.Request, userAgent string, v interface{}) error {
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("directory request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("directory returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse directory response: %w", err)
+	}
+	return nil
+}
+
+// normalizeLimit returns limit, or DefaultLimit if it isn't positive
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return limit
+}
@@ -0,0 +1,161 @@
+package directory
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestITunes_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("term") != "go time" || q.Get("media") != "podcast" || q.Get("limit") != "10" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+  "resultCount": 2,
+  "results": [
+    {"collectionName": "Go Time", "artistName": "Changelog Media", "feedUrl": "https://changelog.com/gotime/feed",
+     "trackCount": 300, "primaryGenreName": "Technology", "artworkUrl600": "https://example.com/600.jpg"},
+    {"collectionName": "No Feed", "artistName": "Someone", "trackCount": 5}
+  ]
+}`))
+	}))
+	defer server.Close()
+
+	results, err := NewITunes(server.URL+"/").Search(context.Background(), "go time", 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	want := Result{
+		Title:        "Go Time",
+		Author:       "Changelog Media",
+		FeedURL:      "https://changelog.com/gotime/feed",
+		EpisodeCount: 300,
+		Genre:        "Technology",
+		ArtworkURL:   "https://example.com/600.jpg",
+	}
+	if results[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, results[0])
+	}
+	if results[1].FeedURL != "" {
+		t.Errorf("Expected missing feed URL to stay empty, got %q", results[1].FeedURL)
+	}
+}
+
+func TestPodcastIndex_Search(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/byterm" || r.URL.Query().Get("q") != "history" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+
+		sum := sha1.Sum([]byte("key" + "secret" + "1700000000"))
+		if r.Header.Get("X-Auth-Key") != "key" || r.Header.Get("X-Auth-Date") != "1700000000" ||
+			r.Header.Get("Authorization") != hex.EncodeToString(sum[:]) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("Expected a User-Agent header")
+		}
+
+		w.Write([]byte(`{
+  "status": "true",
+  "feeds": [
+    {"title": "History Hour", "author": "BBC", "url": "https://example.com/history.xml",
+     "description": "Eyewitness accounts", "episodeCount": 42, "artwork": "https://example.com/art.jpg",
+     "categories": {"102": "Society", "77": "History"}}
+  ],
+  "count": 1
+}`))
+	}))
+	defer server.Close()
+
+	provider := NewPodcastIndex(server.URL, "key", "secret")
+	provider.now = func() time.Time { return now }
+
+	results, err := provider.Search(context.Background(), "history", 0)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	want := Result{
+		Title:        "History Hour",
+		Author:       "BBC",
+		FeedURL:      "https://example.com/history.xml",
+		Description:  "Eyewitness accounts",
+		EpisodeCount: 42,
+		Genre:        "History",
+		ArtworkURL:   "https://example.com/art.jpg",
+	}
+	if results[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, results[0])
+	}
+}
+
+func TestSearch_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if _, err := NewITunes(server.URL).Search(context.Background(), "anything", 5); err == nil {
+		t.Error("Expected an error for a failed request")
+	}
+}
+
+func TestNew_UserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"resultCount": 0, "results": []}`))
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct{ configured, want string }{
+		{"", defaultUserAgent},
+		{"Mozilla/5.0 (X11; Linux x86_64)", "Mozilla/5.0 (X11; Linux x86_64)"},
+	} {
+		provider, err := New(Config{BaseURL: server.URL, UserAgent: tt.configured})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		if _, err := provider.Search(context.Background(), "anything", 5); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Expected User-Agent %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if p, err := New(Config{}); err != nil || p.Name() != "iTunes" {
+		t.Errorf("Expected iTunes by default, got %v, %v", p, err)
+	}
+	if _, err := New(Config{Provider: ProviderPodcastIndex}); err == nil {
+		t.Error("Expected Podcast Index without credentials to fail")
+	}
+	if p, err := New(Config{Provider: "PodcastIndex", APIKey: "k", APISecret: "s"}); err != nil || p.Name() != "Podcast Index" {
+		t.Errorf("Expected Podcast Index, got %v, %v", p, err)
+	}
+	if _, err := New(Config{Provider: "yahoo"}); err == nil {
+		t.Error("Expected an unknown provider to fail")
+	}
+}
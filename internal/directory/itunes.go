@@ -0,0 +1,79 @@
+package directory
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultITunesBaseURL is the location of the iTunes Search API
+const DefaultITunesBaseURL = "https://itunes.apple.com"
+
+// ITunes searches Apple's podcast directory with the iTunes Search API
+type ITunes struct {
+	baseURL   string
+	userAgent string
+}
+
+// NewITunes returns an iTunes provider; an empty baseURL uses DefaultITunesBaseURL
+func NewITunes(baseURL string) *ITunes {
+	if baseURL == "" {
+		baseURL = DefaultITunesBaseURL
+	}
+	return &ITunes{baseURL: strings.TrimRight(baseURL, "/"), userAgent: defaultUserAgent}
+}
+
+func (p *ITunes) Name() string {
+	return "iTunes"
+}
+
+type itunesResponse struct {
+	ResultCount int `json:"resultCount"`
+	Results     []struct {
+		CollectionName   string `json:"collectionName"`
+		ArtistName       string `json:"artistName"`
+		FeedURL          string `json:"feedUrl"`
+		TrackCount       int    `json:"trackCount"`
+		PrimaryGenreName string `json:"primaryGenreName"`
+		ArtworkURL600    string `json:"artworkUrl600"`
+		ArtworkURL100    string `json:"artworkUrl100"`
+	} `json:"results"`
+}
+
+func (p *ITunes) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	params := url.Values{}
+	params.Set("media", "podcast")
+	params.Set("entity", "podcast")
+	params.Set("term", query)
+	params.Set("limit", strconv.Itoa(normalizeLimit(limit)))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp itunesResponse
+	if err := getJSON(req, p.userAgent, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		artwork := r.ArtworkURL600
+		if artwork == "" {
+			artwork = r.ArtworkURL100
+		}
+		// The search API doesn't return show descriptions
+		results = append(results, Result{
+			Title:        r.CollectionName,
+			Author:       r.ArtistName,
+			FeedURL:      r.FeedURL,
+			EpisodeCount: r.TrackCount,
+			Genre:        r.PrimaryGenreName,
+			ArtworkURL:   artwork,
+		})
+	}
+	return results, nil
+}
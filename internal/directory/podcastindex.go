@@ -0,0 +1,127 @@
+package directory
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPodcastIndexBaseURL is the location of the Podcast Index API
+const DefaultPodcastIndexBaseURL = "https://api.podcastindex.org/api/1.0"
+
+// PodcastIndex searches podcastindex.org, which requires a free API key
+type PodcastIndex struct {
+	baseURL   string
+	apiKey    string
+	apiSecret string
+	userAgent string
+
+	// now is replaceable for tests of the request signature
+	now func() time.Time
+}
+
+// NewPodcastIndex returns a Podcast Index provider; an empty baseURL uses
+// DefaultPodcastIndexBaseURL
+func NewPodcastIndex(baseURL, apiKey, apiSecret string) *PodcastIndex {
+	if baseURL == "" {
+		baseURL = DefaultPodcastIndexBaseURL
+	}
+	return &PodcastIndex{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		userAgent: defaultUserAgent,
+		now:       time.Now,
+	}
+}
+
+func (p *PodcastIndex) Name() string {
+	return "Podcast Index"
+}
+
+type podcastIndexResponse struct {
+	Status string `json:"status"`
+	Feeds  []struct {
+		Title        string            `json:"title"`
+		Author       string            `json:"author"`
+		URL          string            `json:"url"`
+		Description  string            `json:"description"`
+		EpisodeCount int               `json:"episodeCount"`
+		Artwork      string            `json:"artwork"`
+		Image        string            `json:"image"`
+		Categories   map[string]string `json:"categories"`
+	} `json:"feeds"`
+}
+
+func (p *PodcastIndex) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("max", strconv.Itoa(normalizeLimit(limit)))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/search/byterm?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	p.sign(req)
+
+	var resp podcastIndexResponse
+	if err := getJSON(req, p.userAgent, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Feeds))
+	for _, f := range resp.Feeds {
+		artwork := f.Artwork
+		if artwork == "" {
+			artwork = f.Image
+		}
+		results = append(results, Result{
+			Title:        f.Title,
+			Author:       f.Author,
+			FeedURL:      f.URL,
+			Description:  f.Description,
+			EpisodeCount: f.EpisodeCount,
+			Genre:        firstCategory(f.Categories),
+			ArtworkURL:   artwork,
+		})
+	}
+	return results, nil
+}
+
+// sign adds the Podcast Index authentication headers: the key, the current Unix
+// time, and the SHA-1 of key, secret and time
+func (p *PodcastIndex) sign(req *http.Request) {
+	date := strconv.FormatInt(p.now().Unix(), 10)
+	sum := sha1.Sum([]byte(p.apiKey + p.apiSecret + date))
+	req.Header.Set("X-Auth-Key", p.apiKey)
+	req.Header.Set("X-Auth-Date", date)
+	req.Header.Set("Authorization", hex.EncodeToString(sum[:]))
+}
+
+// firstCategory returns the category with the lowest ID
+func firstCategory(categories map[string]string) string {
+	if len(categories) == 0 {
+		return ""
+	}
+	ids := make([]int, 0, len(categories))
+	names := make(map[int]string, len(categories))
+	for id, name := range categories {
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, n)
+		names[n] = name
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	sort.Ints(ids)
+	return names[ids[0]]
+}
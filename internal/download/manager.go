@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/csams/podcast-tui/internal/credentials"
 	"github.com/csams/podcast-tui/internal/models"
 )
 
@@ -28,8 +29,13 @@ type Manager struct {
 	stopCh          chan struct{}
 	running         bool
 	configDir       string
+	credentials     CredentialLookup
+	rewriteURL      func(string) string
 }
 
+// CredentialLookup returns the credential for downloading an episode, or nil
+type CredentialLookup func(episode *models.Episode) *credentials.Credential
+
 // NewManager creates a new download manager
 func NewManager(configDir string) *Manager {
 	configManager := NewConfigManager(configDir)
@@ -60,6 +66,28 @@ func NewManager(configDir string) *Manager {
 	return manager
 }
 
+// SetCredentialLookup sets how credentials for private enclosures are found
+func (m *Manager) SetCredentialLookup(lookup CredentialLookup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.credentials = lookup
+}
+
+// SetUserAgent sets the User-Agent sent with downloads. Call it before Start.
+func (m *Manager) SetUserAgent(userAgent string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.downloader.userAgent = userAgent
+}
+
+// SetURLRewriter sets a function applied to enclosure URLs before they are
+// requested, such as one removing tracking prefixes
+func (m *Manager) SetURLRewriter(rewrite func(string) string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rewriteURL = rewrite
+}
+
 // Start initializes and starts the download manager
 func (m *Manager) Start() error {
 	m.mu.Lock()
@@ -408,8 +436,25 @@ func (m *Manager) processDownload(task *DownloadTask) {
 		StartTime: time.Now(),
 	}
 
+	// Authenticate requests for private feeds
+	var requestOpts []RequestOption
+	m.mu.RLock()
+	lookup := m.credentials
+	rewrite := m.rewriteURL
+	m.mu.RUnlock()
+	if lookup != nil {
+		if cred := lookup(task.Episode); cred != nil {
+			requestOpts = append(requestOpts, WithCredential(cred))
+		}
+	}
+
+	enclosureURL := task.Episode.URL
+	if rewrite != nil {
+		enclosureURL = rewrite(enclosureURL)
+	}
+
 	// Get file size first
-	totalSize, err := m.downloader.GetFileSize(task.Context, task.Episode.URL)
+	totalSize, err := m.downloader.GetFileSize(task.Context, enclosureURL, requestOpts...)
 	if err != nil {
 		log.Printf("Failed to get file size for %s: %v", task.Episode.Title, err)
 		// Continue with unknown size
@@ -481,7 +526,7 @@ func (m *Manager) processDownload(task *DownloadTask) {
 		m.registry.UpdateProgress(progress)
 
 		// Attempt download
-		err = m.downloader.DownloadFile(task.Context, task.Episode.URL, filename, progressCallback)
+		err = m.downloader.DownloadFile(task.Context, enclosureURL, filename, progressCallback, requestOpts...)
 		if err == nil {
 			// Success!
 			m.handleDownloadSuccess(task, podcastDir, filename)
@@ -9,6 +9,9 @@ import (
 	"path/filepath"
 	"strconv"
 	"time"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/privacy"
 )
 
 // ProgressReader wraps an io.Reader to track download progress
@@ -71,13 +74,47 @@ func NewDownloader(tempDir, targetDir string) *Downloader {
 		},
 		tempDir:    tempDir,
 		targetDir:  targetDir,
-		userAgent:  "podcast-tui/1.0",
+		userAgent:  privacy.DefaultUserAgent,
 		maxRetries: 5,
 	}
 }
 
+// RequestOption customizes the HTTP requests made by the downloader
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	credential *credentials.Credential
+}
+
+// WithCredential authenticates requests with cred, which is only sent to hosts it allows
+func WithCredential(cred *credentials.Credential) RequestOption {
+	return func(o *requestOptions) {
+		o.credential = cred
+	}
+}
+
+// prepareRequest applies the request options to req and returns the client to send it with
+func (d *Downloader) prepareRequest(req *http.Request, opts []RequestOption) *http.Client {
+	req.Header.Set("User-Agent", d.userAgent)
+
+	var options requestOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.credential == nil || !options.credential.Allows(req.URL.String()) {
+		return d.client
+	}
+
+	options.credential.Apply(req)
+
+	// Keep the credential off hosts we are redirected to that may not receive it
+	client := *d.client
+	client.CheckRedirect = options.credential.CheckRedirect
+	return &client
+}
+
 // DownloadFile downloads a file with progress tracking and resume support
-func (d *Downloader) DownloadFile(ctx context.Context, url, filename string, progressCallback func(current, total, speed int64)) error {
+func (d *Downloader) DownloadFile(ctx context.Context, url, filename string, progressCallback func(current, total, speed int64), opts ...RequestOption) error {
 	tempPath := filepath.Join(d.tempDir, filename+".tmp")
 	targetPath := filepath.Join(d.targetDir, filename)
 
@@ -98,13 +135,13 @@ func (d *Downloader) DownloadFile(ctx context.Context, url, filename string, pro
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", d.userAgent)
+	client := d.prepareRequest(req, opts)
 	if resumeBytes > 0 {
 		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", resumeBytes))
 	}
 
 	// Execute request
-	resp, err := d.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to download file: %w", err)
 	}
@@ -178,15 +215,15 @@ func (d *Downloader) DownloadFile(ctx context.Context, url, filename string, pro
 }
 
 // GetFileSize returns the size of a remote file without downloading it
-func (d *Downloader) GetFileSize(ctx context.Context, url string) (int64, error) {
+func (d *Downloader) GetFileSize(ctx context.Context, url string, opts ...RequestOption) (int64, error) {
 	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create HEAD request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", d.userAgent)
+	client := d.prepareRequest(req, opts)
 
-	resp, err := d.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get file info: %w", err)
 	}
@@ -12,6 +12,9 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/privacy"
 )
 
 func TestProgressReader(t *testing.T) {
@@ -105,8 +108,8 @@ func TestNewDownloader(t *testing.T) {
 		t.Error("Expected HTTP client to be initialized")
 	}
 
-	if downloader.userAgent != "podcast-tui/1.0" {
-		t.Errorf("Expected user agent 'podcast-tui/1.0', got '%s'", downloader.userAgent)
+	if downloader.userAgent != privacy.DefaultUserAgent {
+		t.Errorf("Expected user agent '%s', got '%s'", privacy.DefaultUserAgent, downloader.userAgent)
 	}
 
 	if downloader.maxRetries != 5 {
@@ -192,8 +195,8 @@ func TestDownloader_DownloadFile_Success(t *testing.T) {
 
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("User-Agent") != "podcast-tui/1.0" {
-			t.Errorf("Expected User-Agent 'podcast-tui/1.0', got '%s'", r.Header.Get("User-Agent"))
+		if r.Header.Get("User-Agent") != privacy.DefaultUserAgent {
+			t.Errorf("Expected User-Agent '%s', got '%s'", privacy.DefaultUserAgent, r.Header.Get("User-Agent"))
 		}
 
 		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(testData)))
@@ -531,3 +534,86 @@ func TestDownloader_LargeFileSimulation(t *testing.T) {
 		t.Errorf("Expected multiple progress updates, got %d", len(progressUpdates))
 	}
 }
+
+func TestDownloader_DownloadFile_WithCredential(t *testing.T) {
+	testData := "premium episode content"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "secret" || r.Header.Get("X-Api-Key") != "abc123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(testData)))
+		w.Write([]byte(testData))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	downloader := NewDownloader(tempDir, tempDir)
+
+	// Without credentials the server refuses the download
+	if err := downloader.DownloadFile(context.Background(), server.URL, "denied.mp3", nil); err == nil {
+		t.Fatal("Expected unauthenticated download to fail")
+	}
+
+	store, err := credentials.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Set(server.URL+"/feed.xml", &credentials.Credential{
+		Username: "user",
+		Password: "secret",
+		Headers:  map[string]string{"X-Api-Key": "abc123"},
+	})
+	cred := store.Get(server.URL + "/feed.xml")
+
+	size, err := downloader.GetFileSize(context.Background(), server.URL, WithCredential(cred))
+	if err != nil {
+		t.Fatalf("Failed to get file size: %v", err)
+	}
+	if size != int64(len(testData)) {
+		t.Errorf("Expected size %d, got %d", len(testData), size)
+	}
+
+	if err := downloader.DownloadFile(context.Background(), server.URL, "premium.mp3", nil, WithCredential(cred)); err != nil {
+		t.Fatalf("Failed to download file: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "premium.mp3"))
+	if err != nil {
+		t.Fatalf("Failed to read downloaded file: %v", err)
+	}
+	if string(content) != testData {
+		t.Errorf("Expected content %q, got %q", testData, string(content))
+	}
+}
+
+func TestDownloader_CredentialNotSentToOtherHosts(t *testing.T) {
+	var received bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Authorization") != "" || r.Header.Get("X-Api-Key") != ""
+		w.Header().Set("Content-Length", "4")
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	store, err := credentials.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Set("https://premium.example.com/feed.xml", &credentials.Credential{
+		Username: "user",
+		Password: "secret",
+		Headers:  map[string]string{"X-Api-Key": "abc123"},
+	})
+	cred := store.Get("https://premium.example.com/feed.xml")
+
+	tempDir := t.TempDir()
+	downloader := NewDownloader(tempDir, tempDir)
+	if err := downloader.DownloadFile(context.Background(), server.URL, "episode.mp3", nil, WithCredential(cred)); err != nil {
+		t.Fatalf("Failed to download file: %v", err)
+	}
+	if received {
+		t.Error("Expected credential not to be sent to a host other than the feed's")
+	}
+}
@@ -0,0 +1,103 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// atomFeed is an Atom (RFC 4287) feed; only the parts podcasts use are decoded
+type atomFeed struct {
+	Title      atomText    `xml:"title"`
+	Subtitle   atomText    `xml:"subtitle"`
+	Links      []Link      `xml:"link"`
+	Icon       string      `xml:"icon"`
+	Logo       string      `xml:"logo"`
+	NewFeedURL string      `xml:"new-feed-url"`
+	Entries    []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     atomText   `xml:"title"`
+	Summary   atomText   `xml:"summary"`
+	Content   atomText   `xml:"content"`
+	Links     []atomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Duration  string     `xml:"duration"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// atomText is a text construct, which holds plain text, escaped HTML or inline XHTML
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// atomFormat is Atom, used by some podcast hosts and blog platforms
+type atomFormat struct{}
+
+func (atomFormat) Name() string {
+	return "Atom"
+}
+
+func (atomFormat) Detect(root xml.StartElement) bool {
+	return root.Name.Local == "feed"
+}
+
+func (atomFormat) Decode(decoder *xml.Decoder, root xml.StartElement) (*Channel, error) {
+	var feed atomFeed
+	if err := decoder.DecodeElement(&feed, &root); err != nil {
+		return nil, err
+	}
+
+	channel := &Channel{
+		Title:       feed.Title.String(),
+		Description: feed.Subtitle.String(),
+		Links:       feed.Links,
+		NewFeedURL:  feed.NewFeedURL,
+		Items:       make([]Item, 0, len(feed.Entries)),
+	}
+	channel.Image.URL = feed.Logo
+	if channel.Image.URL == "" {
+		channel.Image.URL = feed.Icon
+	}
+
+	for _, entry := range feed.Entries {
+		item := Item{
+			GUID:        entry.ID,
+			Title:       entry.Title.String(),
+			Description: entry.Content.String(),
+			PubDate:     entry.Published,
+			Duration:    entry.Duration,
+		}
+		if item.Description == "" {
+			item.Description = entry.Summary.String()
+		}
+		if item.PubDate == "" {
+			item.PubDate = entry.Updated
+		}
+		// The media is linked with rel="enclosure", possibly in several formats
+		for _, link := range entry.Links {
+			if strings.EqualFold(link.Rel, "enclosure") {
+				item.Enclosures = append(item.Enclosures, Enclosure{URL: link.Href, Type: link.Type, Length: link.Length})
+			}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	return channel, nil
+}
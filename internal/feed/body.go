@@ -0,0 +1,131 @@
+package feed
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// DefaultMaxFeedBytes is the largest feed, after decompression, that is parsed
+// when Options.MaxBytes isn't set
+const DefaultMaxFeedBytes = 50 << 20
+
+// ErrFeedTooLarge is returned when a feed exceeds the maximum size
+var ErrFeedTooLarge = errors.New("feed exceeds maximum size")
+
+// debugSampleSize is how much of the body is kept for logging parse failures
+const debugSampleSize = 500
+
+// limitedReader fails with ErrFeedTooLarge once more than max bytes are read,
+// rather than silently truncating like io.LimitReader
+type limitedReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n, fmt.Errorf("%w of %d bytes", ErrFeedTooLarge, l.max)
+	}
+	return n, err
+}
+
+// sampleWriter keeps the first bytes written to it
+type sampleWriter struct {
+	buf []byte
+}
+
+func (s *sampleWriter) Write(p []byte) (int, error) {
+	if room := debugSampleSize - len(s.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		s.buf = append(s.buf, p[:room]...)
+	}
+	return len(p), nil
+}
+
+func (s *sampleWriter) String() string {
+	if len(s.buf) >= debugSampleSize {
+		return string(s.buf) + "..."
+	}
+	return string(s.buf)
+}
+
+// decompressBody returns a reader for the response body with any gzip or
+// deflate content encoding removed
+func decompressBody(resp *http.Response) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// "deflate" is meant to be zlib-wrapped, but some servers send raw deflate
+		buffered := bufio.NewReader(resp.Body)
+		header, err := buffered.Peek(2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read deflate header: %w", err)
+		}
+		if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+}
+
+// newFeedDecoder returns an XML decoder for body that converts non-UTF-8 feeds.
+// A non-UTF-8 charset in the Content-Type header takes precedence over the XML
+// declaration. A UTF-8 one does not, as servers often send it by default, so
+// the declaration decides.
+func newFeedDecoder(body io.Reader, contentType string) (*xml.Decoder, error) {
+	transcoded := false
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		if label := params["charset"]; label != "" && !isUTF8(label) {
+			enc, err := htmlindex.Get(label)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported charset %q: %w", label, err)
+			}
+			body = enc.NewDecoder().Reader(body)
+			transcoded = true
+		}
+	}
+
+	decoder := xml.NewDecoder(body)
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if transcoded || isUTF8(label) {
+			return input, nil
+		}
+		enc, err := htmlindex.Get(label)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported charset %q: %w", label, err)
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	return decoder, nil
+}
+
+// isUTF8 reports whether the charset label names UTF-8 or plain ASCII
+func isUTF8(label string) bool {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return false
+	}
+	return enc == unicode.UTF8 || strings.EqualFold(label, "us-ascii") || strings.EqualFold(label, "ascii")
+}
@@ -0,0 +1,175 @@
+package feed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// latin1Feed is a feed whose title and description use non-ASCII characters
+const latin1Feed = `<?xml version="1.0" encoding="%s"?>
+<rss version="2.0">
+  <channel>
+    <title>Café Olé</title>
+    <description>Émissions en français – “quoted”</description>
+    <item>
+      <title>Épisode 1</title>
+      <enclosure url="https://example.com/episode1.mp3" type="audio/mpeg" length="1024"/>
+      <pubDate>Mon, 16 Oct 2023 12:00:00 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+// encodeFeed encodes a UTF-8 string with the given charmap
+func encodeFeed(t *testing.T, cm *charmap.Charmap, content string) []byte {
+	t.Helper()
+	encoded, err := cm.NewEncoder().String(content)
+	if err != nil {
+		t.Fatalf("Failed to encode fixture: %v", err)
+	}
+	return []byte(encoded)
+}
+
+func TestFetch_ISO88591Declared(t *testing.T) {
+	// ISO-8859-1 can't represent the curly quotes, so keep to Latin-1 characters
+	content := strings.Replace(fmt.Sprintf(latin1Feed, "ISO-8859-1"), " – “quoted”", "", 1)
+	body := encodeFeed(t, charmap.ISO8859_1, content)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	podcast, err := ParseFeed(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse ISO-8859-1 feed: %v", err)
+	}
+	if podcast.Title != "Café Olé" {
+		t.Errorf("Expected title 'Café Olé', got %q", podcast.Title)
+	}
+	if podcast.Episodes[0].Title != "Épisode 1" {
+		t.Errorf("Expected episode title 'Épisode 1', got %q", podcast.Episodes[0].Title)
+	}
+}
+
+func TestFetch_Windows1252FromContentType(t *testing.T) {
+	// The XML declaration omits the encoding; only the HTTP header names it
+	content := strings.Replace(fmt.Sprintf(latin1Feed, "UTF-8"), ` encoding="UTF-8"`, "", 1)
+	body := encodeFeed(t, charmap.Windows1252, content)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=windows-1252")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	podcast, err := ParseFeed(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse Windows-1252 feed: %v", err)
+	}
+	if podcast.Description != "Émissions en français – “quoted”" {
+		t.Errorf("Unexpected description %q", podcast.Description)
+	}
+}
+
+func TestFetch_CompressedBodies(t *testing.T) {
+	content := fmt.Sprintf(latin1Feed, "UTF-8")
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write([]byte(content))
+	gw.Close()
+
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	zw.Write([]byte(content))
+	zw.Close()
+
+	tests := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"gzip", gzipped.Bytes()},
+		{"deflate", deflated.Bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.Contains(r.Header.Get("Accept-Encoding"), tt.encoding) {
+					t.Errorf("Expected Accept-Encoding to include %s, got %q", tt.encoding, r.Header.Get("Accept-Encoding"))
+				}
+				w.Header().Set("Content-Encoding", tt.encoding)
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			podcast, err := ParseFeed(server.URL)
+			if err != nil {
+				t.Fatalf("Failed to parse %s feed: %v", tt.encoding, err)
+			}
+			if podcast.Title != "Café Olé" {
+				t.Errorf("Expected title 'Café Olé', got %q", podcast.Title)
+			}
+		})
+	}
+}
+
+func TestFetch_OversizedBody(t *testing.T) {
+	// Pad the feed well past the limit
+	padding := "<!-- " + strings.Repeat("x", 64<<10) + " -->"
+	content := strings.Replace(fmt.Sprintf(latin1Feed, "UTF-8"), "<channel>", "<channel>"+padding, 1)
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write([]byte(content))
+	gw.Close()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"content length", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
+			w.Write([]byte(content))
+		}},
+		{"chunked", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(content[:len(content)/2]))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(content[len(content)/2:]))
+		}},
+		{"gzip", func(w http.ResponseWriter, r *http.Request) {
+			// Small on the wire, but over the limit once decompressed
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped.Bytes())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := Fetch(server.URL, Options{MaxBytes: 16 << 10})
+			if !errors.Is(err, ErrFeedTooLarge) {
+				t.Errorf("Expected ErrFeedTooLarge, got %v", err)
+			}
+		})
+	}
+
+	// The same feed parses with the default limit
+	server := httptest.NewServer(tests[0].handler)
+	defer server.Close()
+	if _, err := Fetch(server.URL, Options{}); err != nil {
+		t.Errorf("Expected feed under the default limit to parse, got %v", err)
+	}
+}
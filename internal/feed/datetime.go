@@ -0,0 +1,235 @@
+package feed
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoDateLayouts are ISO 8601 / RFC 3339 variants seen in feeds
+var isoDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// rfcDateLayouts are RFC 822/2822 variants without the weekday, which is
+// stripped before parsing because feeds frequently get it wrong
+var rfcDateLayouts = buildRFCDateLayouts()
+
+func buildRFCDateLayouts() []string {
+	var layouts []string
+	for _, date := range []string{"2 Jan 2006", "2 January 2006", "2 Jan 06", "Jan 2 2006", "January 2 2006"} {
+		for _, clock := range []string{"15:04:05", "15:04"} {
+			for _, zone := range []string{" -0700", " -07:00", " MST", ""} {
+				layouts = append(layouts, date+" "+clock+zone)
+			}
+		}
+		layouts = append(layouts, date)
+	}
+	return layouts
+}
+
+// zoneOffsets maps zone names used in feeds to numeric offsets. Go only knows
+// the offset of the local zone's abbreviation, so others would silently become UTC.
+var zoneOffsets = map[string]string{
+	"UT": "+0000", "UTC": "+0000", "GMT": "+0000", "Z": "+0000",
+	"EST": "-0500", "EDT": "-0400",
+	"CST": "-0600", "CDT": "-0500",
+	"MST": "-0700", "MDT": "-0600",
+	"PST": "-0800", "PDT": "-0700",
+	"AKST": "-0900", "AKDT": "-0800",
+	"HST": "-1000",
+	"BST": "+0100",
+	"WET": "+0000", "WEST": "+0100",
+	"CET": "+0100", "CEST": "+0200",
+	"EET": "+0200", "EEST": "+0300",
+	"MSK": "+0300",
+	"JST": "+0900", "KST": "+0900",
+	"AEST": "+1000", "AEDT": "+1100",
+	"ACST": "+0930", "ACDT": "+1030",
+	"AWST": "+0800",
+	"NZST": "+1200", "NZDT": "+1300",
+}
+
+var weekdayPrefixes = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// parseDate parses a feed publish date, accepting RFC 822/2822 variants (named
+// zones, missing or misspelled weekdays, two-digit years) and ISO 8601
+func parseDate(value string) (time.Time, error) {
+	s := replaceZoneName(normalizeDate(value))
+	if s == "" {
+		return time.Time{}, fmt.Errorf("unable to parse date: %q", value)
+	}
+
+	for _, layout := range isoDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	s = stripWeekday(s)
+	for _, layout := range rfcDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse date: %q", value)
+}
+
+// normalizeDate collapses whitespace and removes noise such as comments and
+// stray commas that don't affect the date
+func normalizeDate(s string) string {
+	// "+0000 (UTC)"
+	if i := strings.Index(s, "("); i > 0 {
+		s = s[:i]
+	}
+	s = strings.ReplaceAll(s, ",", " ")
+	fields := strings.Fields(s)
+	for i, field := range fields {
+		if strings.EqualFold(field, "Sept") {
+			fields[i] = "Sep"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// stripWeekday removes a leading weekday name
+func stripWeekday(s string) string {
+	first, rest, found := strings.Cut(s, " ")
+	if !found || len(first) < 3 {
+		return s
+	}
+	prefix := strings.ToLower(first[:3])
+	for _, weekday := range weekdayPrefixes {
+		if prefix == weekday {
+			return rest
+		}
+	}
+	return s
+}
+
+// replaceZoneName replaces a trailing zone name with its numeric offset
+func replaceZoneName(s string) string {
+	i := strings.LastIndex(s, " ")
+	if i < 0 {
+		return s
+	}
+	if offset, ok := zoneOffsets[strings.ToUpper(s[i+1:])]; ok {
+		return s[:i+1] + offset
+	}
+	return s
+}
+
+// isoDurationPattern matches ISO 8601 durations such as "PT1H2M3S" or "P1DT2H"
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// unitDurationPattern matches one "<number><unit>" component of durations such
+// as "45m", "1h 30m" or "1 hr 5 mins"
+var unitDurationPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(hours|hour|hrs|hr|h|minutes|minute|mins|min|m|seconds|second|secs|sec|s)`)
+
+// numberPattern matches plain non-negative numbers, which strconv.ParseFloat
+// alone would widen to "Inf", "NaN" and exponents
+var numberPattern = regexp.MustCompile(`^\d+(?:\.\d+)?$`)
+
+// parseDuration converts the duration formats found in feeds to a time.Duration:
+// seconds ("3723", "3723.5"), clock times ("1:02:03", "62:03", "1:02:03.500"),
+// ISO 8601 ("PT1H2M3S") and unit strings ("45m", "1h 2m 3s", "62 mins").
+// An empty value is not an error and returns 0.
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return 0, nil
+	}
+
+	if numberPattern.MatchString(s) {
+		return secondsToDuration(parseFloatOrZero(s)), nil
+	}
+
+	if strings.Contains(s, ":") {
+		if d, ok := parseClockDuration(s); ok {
+			return d, nil
+		}
+		return 0, fmt.Errorf("unable to parse duration: %q", value)
+	}
+
+	upper := strings.ToUpper(s)
+	if m := isoDurationPattern.FindStringSubmatch(upper); m != nil && upper != "P" && upper != "PT" {
+		return secondsToDuration(parseFloatOrZero(m[1])*86400 + parseFloatOrZero(m[2])*3600 +
+			parseFloatOrZero(m[3])*60 + parseFloatOrZero(m[4])), nil
+	}
+
+	if d, ok := parseUnitDuration(strings.ToLower(s)); ok {
+		return d, nil
+	}
+
+	return 0, fmt.Errorf("unable to parse duration: %q", value)
+}
+
+// parseClockDuration parses HH:MM:SS or MM:SS, with optional fractional seconds
+func parseClockDuration(s string) (time.Duration, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+
+	var seconds float64
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		// Only the seconds may be fractional
+		if !numberPattern.MatchString(part) || (i < len(parts)-1 && strings.Contains(part, ".")) {
+			return 0, false
+		}
+		seconds = seconds*60 + parseFloatOrZero(part)
+	}
+	return secondsToDuration(seconds), true
+}
+
+// parseUnitDuration parses strings made entirely of "<number><unit>" components
+func parseUnitDuration(s string) (time.Duration, bool) {
+	matches := unitDurationPattern.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return 0, false
+	}
+
+	var seconds float64
+	last := 0
+	for _, m := range matches {
+		// Only separators may appear between components
+		if strings.Trim(s[last:m[0]], " ,") != "" && strings.TrimSpace(s[last:m[0]]) != "and" {
+			return 0, false
+		}
+		n := parseFloatOrZero(s[m[2]:m[3]])
+		switch s[m[4]] {
+		case 'h':
+			seconds += n * 3600
+		case 'm':
+			seconds += n * 60
+		default:
+			seconds += n
+		}
+		last = m[1]
+	}
+	if strings.TrimSpace(s[last:]) != "" {
+		return 0, false
+	}
+	return secondsToDuration(seconds), true
+}
+
+func parseFloatOrZero(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
@@ -0,0 +1,151 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseDate_Variants(t *testing.T) {
+	want := time.Date(2023, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"Sun, 15 Oct 2023 12:00:00 GMT", want},
+		{"Sun, 15 Oct 2023 12:00:00 +0000", want},
+		{"15 Oct 2023 12:00:00 GMT", want},
+		{"Sun,15 Oct 2023 12:00:00 UT", want},
+		{"Sunday, 15 October 2023 12:00:00 UTC", want},
+		{"Mon, 15 Oct 2023 12:00:00 GMT", want}, // wrong weekday
+		{"Sun, 15 Oct 23 12:00:00 GMT", want},
+		{"Sun, 15 Oct 2023 12:00 GMT", want},
+		{"Sun, 15 Oct 2023 08:00:00 EDT", want},
+		{"Sun, 15 Oct 2023 05:00:00 PDT", want},
+		{"Sun, 15 Oct 2023 14:00:00 CEST", want},
+		{"Sun, 15 Oct 2023 12:00:00 +0000 (UTC)", want},
+		{"Sun,  15  Oct  2023  12:00:00  GMT ", want},
+		{"Oct 15, 2023 12:00:00 GMT", want},
+		{"2023-10-15T12:00:00Z", want},
+		{"2023-10-15T14:00:00+02:00", want},
+		{"2023-10-15T12:00:00.000Z", want},
+		{"2023-10-15 12:00:00", want},
+		{"2023-10-15", time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"Fri, 1 Sept 2023 12:00:00 GMT", time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		got, err := parseDate(tc.input)
+		if err != nil {
+			t.Errorf("parseDate(%q) failed: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.expected) {
+			t.Errorf("parseDate(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+
+	for _, input := range []string{"", "   ", "yesterday", "15/10/2023", "Sun, 45 Oct 2023 12:00:00 GMT"} {
+		if got, err := parseDate(input); err == nil {
+			t.Errorf("parseDate(%q) = %v, expected an error", input, got)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"3723", time.Hour + 2*time.Minute + 3*time.Second},
+		{" 3723 ", time.Hour + 2*time.Minute + 3*time.Second},
+		{"3723.5", time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"01:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"1:02:03.500", time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"62:03", 62*time.Minute + 3*time.Second},
+		{"45m", 45 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1h 2m 3s", time.Hour + 2*time.Minute + 3*time.Second},
+		{"1 hr 5 mins", 65 * time.Minute},
+		{"90 minutes", 90 * time.Minute},
+		{"1 hour and 10 minutes", 70 * time.Minute},
+		{"PT1H2M", time.Hour + 2*time.Minute},
+		{"PT45M30S", 45*time.Minute + 30*time.Second},
+		{"P0DT1H", time.Hour},
+		{"pt90s", 90 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		got, err := parseDuration(tc.input)
+		if err != nil {
+			t.Errorf("parseDuration(%q) failed: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("parseDuration(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+
+	for _, input := range []string{"abc", "Inf", "NaN", "1e3", "-5", "1:2:3:4", "1.5:00", "PT", "5 months", "1:xx"} {
+		if got, err := parseDuration(input); err == nil {
+			t.Errorf("parseDuration(%q) = %v, expected an error", input, got)
+		}
+	}
+}
+
+func TestFetch_CountsUnparseableValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Messy Podcast</title>
+    <item>
+      <title>Good</title>
+      <enclosure url="https://example.com/1.mp3" type="audio/mpeg"/>
+      <pubDate>Sun, 15 Oct 2023 12:00:00 EST</pubDate>
+      <itunes:duration>PT1H2M</itunes:duration>
+    </item>
+    <item>
+      <title>Bad date</title>
+      <enclosure url="https://example.com/2.mp3" type="audio/mpeg"/>
+      <pubDate>sometime last week</pubDate>
+      <itunes:duration>about an hour</itunes:duration>
+    </item>
+    <item>
+      <title>Also bad</title>
+      <enclosure url="https://example.com/3.mp3" type="audio/mpeg"/>
+      <pubDate>32/13/2023</pubDate>
+    </item>
+  </channel>
+</rss>`))
+	}))
+	defer server.Close()
+
+	podcast, err := ParseFeed(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse feed: %v", err)
+	}
+
+	if got := podcast.Episodes[0].Duration; got != time.Hour+2*time.Minute {
+		t.Errorf("Expected ISO 8601 duration to be parsed, got %v", got)
+	}
+	if got := podcast.Episodes[0].PublishDate; !got.Equal(time.Date(2023, 10, 15, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected named zone to be honored, got %v", got.UTC())
+	}
+
+	issues := podcast.ParseIssues
+	if issues == nil {
+		t.Fatal("Expected parse issues to be recorded")
+	}
+	if issues.Dates != 2 || issues.DateSample != "sometime last week" {
+		t.Errorf("Unexpected date issues: %+v", issues)
+	}
+	if issues.Durations != 1 || issues.DurationSample != "about an hour" {
+		t.Errorf("Unexpected duration issues: %+v", issues)
+	}
+}
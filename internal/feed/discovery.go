@@ -0,0 +1,156 @@
+package feed
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	neturl "net/url"
+	"regexp"
+	"strings"
+)
+
+// DiscoveredFeed is a feed advertised by a web page with <link rel="alternate">
+type DiscoveredFeed struct {
+	URL   string
+	Title string
+	Type  string
+}
+
+// NotFeedError is returned when a URL serves a web page rather than a feed.
+// Feeds lists the feeds the page advertises, RSS first.
+type NotFeedError struct {
+	URL   string
+	Feeds []DiscoveredFeed
+}
+
+func (e *NotFeedError) Error() string {
+	switch len(e.Feeds) {
+	case 0:
+		return fmt.Sprintf("%s is a web page, not a feed, and doesn't advertise one", e.URL)
+	case 1:
+		return fmt.Sprintf("%s is a web page advertising the feed %s", e.URL, e.Feeds[0].URL)
+	default:
+		return fmt.Sprintf("%s is a web page advertising %d feeds", e.URL, len(e.Feeds))
+	}
+}
+
+// feedTypes are the link types recognized as feeds, in order of preference
+var feedTypes = []string{"application/rss+xml", "application/atom+xml"}
+
+// htmlSniffSize is how much of a response is inspected to tell web pages from feeds
+const htmlSniffSize = 512
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	linkTagPattern     = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	baseTagPattern     = regexp.MustCompile(`(?is)<base\b[^>]*>`)
+	attrPattern        = regexp.MustCompile(`(?s)([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+))`)
+)
+
+// looksLikeHTML reports whether the start of the response is a web page
+func looksLikeHTML(r *bufio.Reader) bool {
+	head, _ := r.Peek(htmlSniffSize)
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	start := strings.ToLower(string(bytes.TrimLeft(head, " \t\r\n")))
+
+	if strings.HasPrefix(start, "<!doctype html") || strings.HasPrefix(start, "<html") {
+		return true
+	}
+	if strings.Contains(start, "<rss") || strings.Contains(start, "<feed") || strings.Contains(start, "<rdf") {
+		return false
+	}
+	return strings.Contains(start, "<html")
+}
+
+// discoverFeeds returns the feeds advertised by an HTML page, with URLs resolved
+// against the page's URL (or its <base href>)
+func discoverFeeds(page string, pageURL *neturl.URL) []DiscoveredFeed {
+	page = htmlCommentPattern.ReplaceAllString(page, "")
+
+	base := pageURL
+	if tag := baseTagPattern.FindString(page); tag != "" {
+		if href := parseAttrs(tag)["href"]; href != "" {
+			if ref, err := neturl.Parse(href); err == nil {
+				base = pageURL.ResolveReference(ref)
+			}
+		}
+	}
+
+	byType := make(map[string][]DiscoveredFeed)
+	seen := make(map[string]bool)
+	for _, tag := range linkTagPattern.FindAllString(page, -1) {
+		attrs := parseAttrs(tag)
+		if !hasToken(attrs["rel"], "alternate") || attrs["href"] == "" {
+			continue
+		}
+		feedType := strings.ToLower(strings.TrimSpace(attrs["type"]))
+		if i := strings.Index(feedType, ";"); i >= 0 {
+			feedType = strings.TrimSpace(feedType[:i])
+		}
+		if !isFeedType(feedType) {
+			continue
+		}
+
+		ref, err := neturl.Parse(attrs["href"])
+		if err != nil {
+			continue
+		}
+		feedURL := base.ResolveReference(ref)
+		if feedURL.Scheme != "http" && feedURL.Scheme != "https" {
+			continue
+		}
+		if seen[feedURL.String()] {
+			continue
+		}
+		seen[feedURL.String()] = true
+
+		byType[feedType] = append(byType[feedType], DiscoveredFeed{
+			URL:   feedURL.String(),
+			Title: strings.TrimSpace(attrs["title"]),
+			Type:  feedType,
+		})
+	}
+
+	var feeds []DiscoveredFeed
+	for _, feedType := range feedTypes {
+		feeds = append(feeds, byType[feedType]...)
+	}
+	return feeds
+}
+
+// parseAttrs returns the attributes of an HTML tag with lowercased names and
+// unescaped values
+func parseAttrs(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range attrPattern.FindAllStringSubmatch(tag, -1) {
+		value := m[2]
+		if value == "" {
+			value = m[3]
+		}
+		if value == "" {
+			value = m[4]
+		}
+		attrs[strings.ToLower(m[1])] = strings.TrimSpace(html.UnescapeString(value))
+	}
+	return attrs
+}
+
+// hasToken reports whether a space-separated attribute value contains token
+func hasToken(value, token string) bool {
+	for _, field := range strings.Fields(value) {
+		if strings.EqualFold(field, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func isFeedType(feedType string) bool {
+	for _, t := range feedTypes {
+		if feedType == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package feed
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"testing"
+)
+
+func TestDiscoverFeeds(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html>
+<head>
+  <title>My Show</title>
+  <link rel="stylesheet" href="/style.css">
+  <link rel="alternate" hreflang="fr" href="/fr/">
+  <!-- <link rel="alternate" type="application/rss+xml" href="/old.xml"> -->
+  <link rel="alternate" type="application/atom+xml" title="Atom" href="atom.xml">
+  <LINK REL="Alternate" TYPE="application/rss+xml; charset=utf-8" TITLE="My Show &amp; Friends" HREF='/feed.xml'>
+  <link type="application/rss+xml" rel="alternate home" href="https://feeds.example.net/show">
+  <link rel="alternate" type="application/rss+xml" href="/feed.xml">
+</head>
+<body></body>
+</html>`
+
+	pageURL, _ := neturl.Parse("https://example.com/shows/mine/")
+	feeds := discoverFeeds(page, pageURL)
+
+	want := []DiscoveredFeed{
+		{URL: "https://example.com/feed.xml", Title: "My Show & Friends", Type: "application/rss+xml"},
+		{URL: "https://feeds.example.net/show", Type: "application/rss+xml"},
+		{URL: "https://example.com/shows/mine/atom.xml", Title: "Atom", Type: "application/atom+xml"},
+	}
+	if len(feeds) != len(want) {
+		t.Fatalf("Expected %d feeds, got %d: %+v", len(want), len(feeds), feeds)
+	}
+	for i := range want {
+		if feeds[i] != want[i] {
+			t.Errorf("Feed %d: expected %+v, got %+v", i, want[i], feeds[i])
+		}
+	}
+}
+
+func TestDiscoverFeeds_BaseHref(t *testing.T) {
+	page := `<html><head>
+<base href="https://cdn.example.com/site/">
+<link rel="alternate" type="application/rss+xml" href="podcast.rss">
+</head></html>`
+
+	pageURL, _ := neturl.Parse("https://example.com/")
+	feeds := discoverFeeds(page, pageURL)
+	if len(feeds) != 1 || feeds[0].URL != "https://cdn.example.com/site/podcast.rss" {
+		t.Errorf("Expected feed resolved against <base href>, got %+v", feeds)
+	}
+}
+
+func TestFetch_WebPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<!doctype html><html><head>
+<link rel="alternate" type="application/rss+xml" title="Episodes" href="/feed.xml">
+</head><body>Welcome</body></html>`))
+		case "/about":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body>No feeds here</body></html>`))
+		}
+	}))
+	defer server.Close()
+
+	_, err := Fetch(server.URL+"/", Options{})
+	var notFeed *NotFeedError
+	if !errors.As(err, &notFeed) {
+		t.Fatalf("Expected a NotFeedError, got %v", err)
+	}
+	if len(notFeed.Feeds) != 1 || notFeed.Feeds[0].URL != server.URL+"/feed.xml" || notFeed.Feeds[0].Title != "Episodes" {
+		t.Errorf("Unexpected discovered feeds: %+v", notFeed.Feeds)
+	}
+
+	_, err = Fetch(server.URL+"/about", Options{})
+	if !errors.As(err, &notFeed) || len(notFeed.Feeds) != 0 {
+		t.Errorf("Expected a NotFeedError without feeds, got %v", err)
+	}
+}
+
+func TestFetch_FeedServedAsHTML(t *testing.T) {
+	// Misconfigured servers send feeds as text/html; the content decides
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Mislabeled</title></channel></rss>`))
+	}))
+	defer server.Close()
+
+	podcast, err := ParseFeed(server.URL)
+	if err != nil {
+		t.Fatalf("Expected feed to parse despite its content type: %v", err)
+	}
+	if podcast.Title != "Mislabeled" {
+		t.Errorf("Unexpected title %q", podcast.Title)
+	}
+}
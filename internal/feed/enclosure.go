@@ -0,0 +1,141 @@
+package feed
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// AlternateEnclosure is a <podcast:alternateEnclosure>, another format or quality
+// of the episode, available from one or more sources
+type AlternateEnclosure struct {
+	Type    string `xml:"type,attr"`
+	Length  string `xml:"length,attr"`
+	Bitrate string `xml:"bitrate,attr"` // bit/s
+	Lang    string `xml:"lang,attr"`
+	Title   string `xml:"title,attr"`
+	Sources []struct {
+		URI string `xml:"uri,attr"`
+	} `xml:"source"`
+}
+
+// MediaContent is a Media RSS <media:content>
+type MediaContent struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr"`
+	Medium   string `xml:"medium,attr"`
+	FileSize string `xml:"fileSize,attr"`
+	Bitrate  string `xml:"bitrate,attr"` // kbit/s
+	Lang     string `xml:"lang,attr"`
+}
+
+// MediaGroup is a Media RSS <media:group> of contents that are versions of the same media
+type MediaGroup struct {
+	Contents []MediaContent `xml:"content"`
+}
+
+// enclosures returns every media file the item is offered as, the feed's own
+// <enclosure> first. Alternatives that can't be fetched over HTTP, such as
+// IPFS or torrent sources, and images are left out.
+func (item Item) enclosures() []models.Enclosure {
+	var result []models.Enclosure
+	index := make(map[string]int)
+	add := func(e models.Enclosure) {
+		e.URL = strings.TrimSpace(e.URL)
+		if e.URL == "" {
+			return
+		}
+		i, seen := index[e.URL]
+		if !seen {
+			index[e.URL] = len(result)
+			result = append(result, e)
+			return
+		}
+		// The same file described again, often with more detail
+		existing := &result[i]
+		if existing.Type == "" {
+			existing.Type = e.Type
+		}
+		if existing.Length == 0 {
+			existing.Length = e.Length
+		}
+		if existing.Bitrate == 0 {
+			existing.Bitrate = e.Bitrate
+		}
+		if existing.Language == "" {
+			existing.Language = e.Language
+		}
+		if existing.Title == "" {
+			existing.Title = e.Title
+		}
+	}
+
+	for _, enclosure := range item.Enclosures {
+		add(models.Enclosure{URL: enclosure.URL, Type: enclosure.Type, Length: parseLength(enclosure.Length)})
+	}
+	for _, alternate := range item.AlternateEnclosures {
+		if isImage(alternate.Type, "") {
+			continue
+		}
+		for _, source := range alternate.Sources {
+			if !isWebURL(source.URI) {
+				continue
+			}
+			add(models.Enclosure{
+				URL:      source.URI,
+				Type:     alternate.Type,
+				Length:   parseLength(alternate.Length),
+				Bitrate:  int(math.Round(parseNumber(alternate.Bitrate) / 1000)),
+				Language: alternate.Lang,
+				Title:    alternate.Title,
+			})
+			break
+		}
+	}
+
+	contents := item.MediaContents
+	for _, group := range item.MediaGroups {
+		contents = append(contents, group.Contents...)
+	}
+	for _, content := range contents {
+		if isImage(content.Type, content.Medium) || !isWebURL(content.URL) {
+			continue
+		}
+		add(models.Enclosure{
+			URL:      content.URL,
+			Type:     content.Type,
+			Length:   parseLength(content.FileSize),
+			Bitrate:  int(math.Round(parseNumber(content.Bitrate))),
+			Language: content.Lang,
+		})
+	}
+	return result
+}
+
+func isWebURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isImage(mimeType, medium string) bool {
+	return strings.EqualFold(medium, "image") || strings.HasPrefix(strings.ToLower(mimeType), "image/")
+}
+
+func parseLength(s string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func parseNumber(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0
+	}
+	return f
+}
@@ -0,0 +1,102 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Format parses one kind of feed document, such as RSS or Atom. The web source
+// detects the format of each document from its root element.
+type Format interface {
+	// Name describes the format in errors and logs
+	Name() string
+
+	// Detect reports whether a document with this root element is in the format
+	Detect(root xml.StartElement) bool
+
+	// Decode parses the rest of the document into the channel it describes
+	Decode(decoder *xml.Decoder, root xml.StartElement) (*Channel, error)
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = []Format{rssFormat{}, atomFormat{}}
+)
+
+// RegisterFormat adds a feed format for the web source to detect
+func RegisterFormat(format Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats = append(formats, format)
+}
+
+// UnknownFormatError is returned for XML documents that are not in any registered format
+type UnknownFormatError struct {
+	Root string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("unrecognized feed format with root element <%s>", e.Root)
+}
+
+// decodeFeed detects the format of the document and decodes it, returning the
+// format's name so errors can say what failed to parse
+func decodeFeed(decoder *xml.Decoder) (*Channel, string, error) {
+	root, err := rootElement(decoder)
+	if err != nil {
+		return nil, "feed", err
+	}
+
+	formatsMu.RLock()
+	var format Format
+	for _, f := range formats {
+		if f.Detect(root) {
+			format = f
+			break
+		}
+	}
+	formatsMu.RUnlock()
+
+	if format == nil {
+		return nil, "feed", &UnknownFormatError{Root: root.Name.Local}
+	}
+	channel, err := format.Decode(decoder, root)
+	return channel, format.Name(), err
+}
+
+// rootElement skips the prolog and returns the document's root element
+func rootElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return xml.StartElement{}, fmt.Errorf("no root element: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+// rssFormat is RSS 2.0, the format nearly all podcast feeds use
+type rssFormat struct{}
+
+func (rssFormat) Name() string {
+	return "RSS"
+}
+
+func (rssFormat) Detect(root xml.StartElement) bool {
+	return root.Name.Local == "rss"
+}
+
+func (rssFormat) Decode(decoder *xml.Decoder, root xml.StartElement) (*Channel, error) {
+	var rss RSS
+	if err := decoder.DecodeElement(&rss, &root); err != nil {
+		return nil, err
+	}
+	return &rss.Channel, nil
+}
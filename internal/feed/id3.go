@@ -0,0 +1,139 @@
+package feed
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// id3Frames maps the ID3v2 text frames that are used to tag names matching ffprobe's
+var id3Frames = map[string]string{
+	"TIT2": "title",
+	"TPE1": "artist",
+	"TALB": "album",
+}
+
+// readID3Tags reads the title, artist and album from a file's ID3v2.3 or v2.4 tag.
+// It is the fallback when ffprobe isn't installed, and returns an empty map for
+// files without such a tag.
+func readID3Tags(path string) map[string]string {
+	tags := make(map[string]string)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return tags
+	}
+	defer f.Close()
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(f, header); err != nil || string(header[:3]) != "ID3" {
+		return tags
+	}
+	version := header[3]
+	if version != 3 && version != 4 {
+		return tags
+	}
+	// Tags using unsynchronisation or an extended header are rare; skip them
+	if header[5]&0xc0 != 0 {
+		return tags
+	}
+	remaining := int64(syncsafe(header[6:10]))
+
+	frameHeader := make([]byte, 10)
+	for remaining > 10 && len(tags) < len(id3Frames) {
+		if _, err := io.ReadFull(f, frameHeader); err != nil {
+			break
+		}
+		remaining -= 10
+
+		id := string(frameHeader[:4])
+		if frameHeader[0] == 0 {
+			// Padding
+			break
+		}
+		size := int64(binary.BigEndian.Uint32(frameHeader[4:8]))
+		if version == 4 {
+			size = int64(syncsafe(frameHeader[4:8]))
+		}
+		if size <= 0 || size > remaining {
+			break
+		}
+		remaining -= size
+
+		name, wanted := id3Frames[id]
+		if !wanted {
+			if _, err := f.Seek(size, io.SeekCurrent); err != nil {
+				break
+			}
+			continue
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(f, data); err != nil {
+			break
+		}
+		if text := decodeID3Text(data); text != "" {
+			tags[name] = text
+		}
+	}
+	return tags
+}
+
+// syncsafe decodes a 28-bit "syncsafe" integer, which uses 7 bits of each byte
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
+
+// decodeID3Text decodes a text frame, whose first byte gives its encoding
+func decodeID3Text(data []byte) string {
+	if len(data) < 2 {
+		return ""
+	}
+
+	var text string
+	switch data[0] {
+	case 0: // ISO-8859-1
+		runes := make([]rune, len(data)-1)
+		for i, b := range data[1:] {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	case 1: // UTF-16 with byte order mark
+		text = decodeUTF16(data[1:], true)
+	case 2: // UTF-16BE
+		text = decodeUTF16(data[1:], false)
+	case 3: // UTF-8
+		text = string(data[1:])
+	default:
+		return ""
+	}
+
+	// v2.4 separates multiple values with NUL; keep the first
+	text, _, _ = strings.Cut(text, "\x00")
+	return strings.TrimSpace(text)
+}
+
+func decodeUTF16(b []byte, bom bool) string {
+	littleEndian := false
+	if bom && len(b) >= 2 {
+		switch {
+		case b[0] == 0xff && b[1] == 0xfe:
+			littleEndian = true
+			b = b[2:]
+		case b[0] == 0xfe && b[1] == 0xff:
+			b = b[2:]
+		}
+	}
+
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		if littleEndian {
+			units = append(units, uint16(b[i])|uint16(b[i+1])<<8)
+		} else {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
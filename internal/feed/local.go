@@ -0,0 +1,339 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// audioExtensions are the file types included as episodes of a local folder
+var audioExtensions = map[string]bool{
+	".mp3": true, ".m4a": true, ".m4b": true, ".aac": true, ".mp4": true,
+	".ogg": true, ".oga": true, ".opus": true, ".flac": true, ".wav": true,
+	".wma": true, ".mka": true, ".webm": true,
+}
+
+// probeTimeout bounds how long ffprobe may take for one file
+const probeTimeout = 10 * time.Second
+
+// LocalPath returns the directory or file a file:// URL refers to
+func LocalPath(rawURL string) (string, bool) {
+	if !strings.HasPrefix(rawURL, "file://") {
+		return "", false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// LocalFeedURL turns a folder given as a path ("/srv/lectures", "~/Audiobooks",
+// "./talks") or file:// URL into the file:// URL it is subscribed under. It
+// returns false for anything else, such as http URLs.
+func LocalFeedURL(input string) (string, bool) {
+	path := strings.TrimSpace(input)
+	if p, ok := LocalPath(path); ok {
+		path = p
+	} else if strings.HasPrefix(path, "~/") || path == "~" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		path = filepath.Join(home, path[1:])
+	} else if !filepath.IsAbs(path) && !strings.HasPrefix(path, ".") {
+		return "", false
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	return fileURL(abs), true
+}
+
+// localSource turns folders of audio files into podcasts
+type localSource struct{}
+
+func (localSource) Name() string {
+	return "local folder"
+}
+
+func (localSource) Resolve(input string) (string, bool) {
+	return LocalFeedURL(input)
+}
+
+func (localSource) Fetch(url string, opts Options) (*Result, error) {
+	dir, ok := LocalPath(url)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a folder URL", ErrUnsupportedSource, url)
+	}
+	return fetchLocal(dir, url, opts, nil)
+}
+
+// Refresh rescans the folder. Files unchanged since the last scan keep their
+// episodes' details instead of being probed again.
+func (localSource) Refresh(podcast *models.Podcast, opts Options) (*Result, error) {
+	dir, ok := LocalPath(podcast.URL)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a folder URL", ErrUnsupportedSource, podcast.URL)
+	}
+	known := make(map[string]*models.Episode, len(podcast.Episodes))
+	for _, episode := range podcast.Episodes {
+		known[episode.URL] = episode
+	}
+	return fetchLocal(dir, podcast.URL, opts, known)
+}
+
+// PlaybackLocation plays files in place
+func (localSource) PlaybackLocation(episodeURL string) (string, bool) {
+	if path, ok := LocalPath(episodeURL); ok {
+		return path, true
+	}
+	return episodeURL, false
+}
+
+func (localSource) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+func fileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// fetchLocal builds a podcast from the audio files in dir, including subfolders,
+// reusing the episodes in known, by URL, for files that haven't changed
+func fetchLocal(dir, feedURL string, opts Options, known map[string]*models.Episode) (*Result, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		log.Printf("Feed parser: Local folder %s unavailable: %v", dir, err)
+		return nil, fmt.Errorf("failed to read folder %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a folder", dir)
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable subfolders rather than failing the whole scan
+			log.Printf("Feed parser: Skipping %s: %v", path, err)
+			if d != nil && d.IsDir() && path != dir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && audioExtensions[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read folder %s: %w", dir, err)
+	}
+	sortNatural(files)
+
+	idURL := feedURL
+	if opts.IdentityURL != "" {
+		idURL = opts.IdentityURL
+	}
+
+	podcast := &models.Podcast{
+		Title:       filepath.Base(dir),
+		Description: "Local folder " + dir,
+		URL:         feedURL,
+		Episodes:    make([]*models.Episode, 0, len(files)),
+	}
+	podcast.ConvertedDescription = podcast.Description
+
+	ffprobe, _ := exec.LookPath("ffprobe")
+	authors := make(map[string]bool)
+	for _, path := range files {
+		episode := localEpisode(dir, path, ffprobe, known)
+		if episode == nil {
+			continue
+		}
+		// Files have no publish date of their own, so identify them by path alone
+		episode.ID = models.GenerateEpisodeID(idURL, episode.URL, time.Time{})
+		if episode.PublishDate.After(podcast.LastUpdated) {
+			podcast.LastUpdated = episode.PublishDate
+		}
+		podcast.Episodes = append(podcast.Episodes, episode)
+
+		if episode.Artist != "" {
+			authors[episode.Artist] = true
+		}
+	}
+	if len(authors) == 1 {
+		for author := range authors {
+			podcast.Author = author
+		}
+	}
+
+	log.Printf("Feed parser: Found %d audio files in %s", len(podcast.Episodes), dir)
+	return &Result{Podcast: podcast, Pages: 1}, nil
+}
+
+// localEpisode describes one audio file. The title comes from the file's tags, or
+// its name, and the duration from ffprobe when installed. A file whose size and
+// modification time match its episode in known isn't read.
+func localEpisode(dir, path, ffprobe string, known map[string]*models.Episode) *models.Episode {
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Printf("Feed parser: Skipping %s: %v", path, err)
+		return nil
+	}
+	if episode, ok := unchangedEpisode(known[fileURL(path)], info); ok {
+		return episode
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+
+	episode := &models.Episode{
+		URL:         fileURL(path),
+		PublishDate: info.ModTime(),
+		FileSize:    info.Size(),
+	}
+
+	var tags map[string]string
+	if ffprobe != "" {
+		var duration time.Duration
+		duration, tags = probeFile(ffprobe, path)
+		episode.Duration = duration
+	} else {
+		tags = readID3Tags(path)
+	}
+
+	episode.Title = tags["title"]
+	episode.Artist = tags["artist"]
+	if episode.Title == "" {
+		episode.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	details := []string{"File: " + rel}
+	if album := tags["album"]; album != "" {
+		details = append(details, "Album: "+album)
+	}
+	if artist := tags["artist"]; artist != "" {
+		details = append(details, "Artist: "+artist)
+	}
+	episode.Description = strings.Join(details, "\n")
+	episode.ConvertedDescription = episode.Description
+	return episode
+}
+
+// unchangedEpisode returns a copy of the details of known, the episode of a file
+// from an earlier scan, if the file's size and modification time still match
+func unchangedEpisode(known *models.Episode, info os.FileInfo) (*models.Episode, bool) {
+	if known == nil || known.FileSize != info.Size() || !known.PublishDate.Equal(info.ModTime()) {
+		return nil, false
+	}
+	episode := &models.Episode{
+		URL:                  known.URL,
+		Title:                known.Title,
+		Description:          known.Description,
+		ConvertedDescription: known.ConvertedDescription,
+		Duration:             known.Duration,
+		PublishDate:          info.ModTime(),
+		FileSize:             known.FileSize,
+		Artist:               known.Artist,
+	}
+	return episode, true
+}
+
+// ffprobeOutput is the part of "ffprobe -show_format" output that is used
+type ffprobeOutput struct {
+	Format struct {
+		Duration string            `json:"duration"`
+		Tags     map[string]string `json:"tags"`
+	} `json:"format"`
+}
+
+// probeFile returns the duration and tags (with lowercase names) of an audio file
+func probeFile(ffprobe, path string) (time.Duration, map[string]string) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, ffprobe, "-v", "quiet", "-print_format", "json", "-show_format", path).Output()
+	if err != nil {
+		log.Printf("Feed parser: ffprobe failed for %s: %v", path, err)
+		return 0, readID3Tags(path)
+	}
+
+	var probe ffprobeOutput
+	if err := json.Unmarshal(out, &probe); err != nil {
+		log.Printf("Feed parser: Unexpected ffprobe output for %s: %v", path, err)
+		return 0, readID3Tags(path)
+	}
+
+	tags := make(map[string]string)
+	for name, value := range probe.Format.Tags {
+		tags[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+	seconds, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+	return secondsToDuration(seconds), tags
+}
+
+// sortNatural sorts paths so that numbered files come in numeric order
+// ("Lecture 2" before "Lecture 10")
+func sortNatural(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		a, b := paths[i], paths[j]
+		for a != "" && b != "" {
+			if isDigit(a[0]) && isDigit(b[0]) {
+				na, ra := splitNumber(a)
+				nb, rb := splitNumber(b)
+				if na != nb {
+					// Without leading zeros, a longer number is a larger one
+					if len(na) != len(nb) {
+						return len(na) < len(nb)
+					}
+					return na < nb
+				}
+				a, b = ra, rb
+				continue
+			}
+			ca, cb := strings.ToLower(a[:1]), strings.ToLower(b[:1])
+			if ca != cb {
+				return ca < cb
+			}
+			a, b = a[1:], b[1:]
+		}
+		return len(a) < len(b)
+	})
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitNumber splits the leading digits, without leading zeros, from s
+func splitNumber(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	number := strings.TrimLeft(s[:i], "0")
+	return number, s[i:]
+}
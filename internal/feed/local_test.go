@@ -0,0 +1,185 @@
+package feed
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// id3Tag builds an ID3v2.3 tag with the given text frames, UTF-16 encoded
+func id3Tag(frames map[string]string) []byte {
+	var body []byte
+	for id, text := range frames {
+		data := []byte{1, 0xff, 0xfe}
+		for _, unit := range utf16.Encode([]rune(text)) {
+			data = append(data, byte(unit), byte(unit>>8))
+		}
+		header := make([]byte, 10)
+		copy(header, id)
+		binary.BigEndian.PutUint32(header[4:8], uint32(len(data)))
+		body = append(body, header...)
+		body = append(body, data...)
+	}
+	// Padding
+	body = append(body, make([]byte, 32)...)
+
+	size := len(body)
+	tag := []byte{'I', 'D', '3', 3, 0, 0,
+		byte(size >> 21 & 0x7f), byte(size >> 14 & 0x7f), byte(size >> 7 & 0x7f), byte(size & 0x7f)}
+	return append(tag, body...)
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetch_LocalFolder(t *testing.T) {
+	// Use the ID3 reader rather than whatever ffprobe is installed
+	t.Setenv("PATH", "")
+
+	dir := filepath.Join(t.TempDir(), "Lectures")
+	writeFile(t, filepath.Join(dir, "Lecture 10.mp3"), []byte("audio"))
+	writeFile(t, filepath.Join(dir, "Lecture 2.MP3"), id3Tag(map[string]string{
+		"TIT2": "Thermodynamiques: Entropie",
+		"TPE1": "Prof. Émile",
+		"TALB": "Physique 101",
+	}))
+	writeFile(t, filepath.Join(dir, "Lecture 1.m4a"), []byte("audio"))
+	writeFile(t, filepath.Join(dir, "Extras", "Q&A.opus"), []byte("audio"))
+	writeFile(t, filepath.Join(dir, "notes.txt"), []byte("not audio"))
+	writeFile(t, filepath.Join(dir, ".hidden.mp3"), []byte("audio"))
+	writeFile(t, filepath.Join(dir, ".trash", "deleted.mp3"), []byte("audio"))
+
+	feedURL, ok := LocalFeedURL(dir)
+	if !ok {
+		t.Fatalf("Expected %s to be a local folder", dir)
+	}
+	result, err := Fetch(feedURL, Options{})
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	podcast := result.Podcast
+	if podcast.Title != "Lectures" || podcast.URL != feedURL {
+		t.Errorf("Unexpected podcast %q at %s", podcast.Title, podcast.URL)
+	}
+	if podcast.Author != "Prof. Émile" {
+		t.Errorf("Expected the only artist as author, got %q", podcast.Author)
+	}
+
+	wantTitles := []string{"Q&A", "Lecture 1", "Thermodynamiques: Entropie", "Lecture 10"}
+	if len(podcast.Episodes) != len(wantTitles) {
+		t.Fatalf("Expected %d episodes, got %d", len(wantTitles), len(podcast.Episodes))
+	}
+	for i, title := range wantTitles {
+		if podcast.Episodes[i].Title != title {
+			t.Errorf("Episode %d: expected %q, got %q", i, title, podcast.Episodes[i].Title)
+		}
+	}
+
+	tagged := podcast.Episodes[2]
+	if !strings.Contains(tagged.Description, "Album: Physique 101") {
+		t.Errorf("Expected album in description, got %q", tagged.Description)
+	}
+	path, ok := LocalPath(tagged.URL)
+	if !ok || path != filepath.Join(dir, "Lecture 2.MP3") {
+		t.Errorf("Expected episode URL to refer to the file, got %s", tagged.URL)
+	}
+
+	// Rescanning after a file changes keeps the IDs the queue and positions refer to
+	if err := os.WriteFile(filepath.Join(dir, "Lecture 1.m4a"), []byte("re-encoded"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rescanned, err := Fetch(feedURL, Options{})
+	if err != nil {
+		t.Fatalf("Rescan failed: %v", err)
+	}
+	for i, episode := range rescanned.Podcast.Episodes {
+		if episode.ID != podcast.Episodes[i].ID {
+			t.Errorf("Episode %q changed ID on rescan", episode.Title)
+		}
+	}
+}
+
+func TestRefresh_LocalFolderReusesUnchangedFiles(t *testing.T) {
+	t.Setenv("PATH", "")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "1.mp3"), id3Tag(map[string]string{"TIT2": "One", "TPE1": "Host"}))
+	writeFile(t, filepath.Join(dir, "2.mp3"), []byte("audio"))
+	feedURL, _ := LocalFeedURL(dir)
+	result, err := Fetch(feedURL, Options{})
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	podcast := result.Podcast
+
+	// Stand-ins for details only an expensive probe would find again
+	podcast.Episodes[0].Duration = time.Hour
+	podcast.Episodes[1].Title = "Probed"
+	if err := os.WriteFile(filepath.Join(dir, "2.mp3"), []byte("longer audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := Refresh(podcast, Options{})
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	episodes := refreshed.Podcast.Episodes
+	if episodes[0].Duration != time.Hour || episodes[0].Title != "One" {
+		t.Errorf("Expected the unchanged file's details kept, got %q of %v", episodes[0].Title, episodes[0].Duration)
+	}
+	if episodes[0].Artist != "Host" || refreshed.Podcast.Author != "Host" {
+		t.Errorf("Expected the kept artist as author, got %q", refreshed.Podcast.Author)
+	}
+	if episodes[1].Title != "2" {
+		t.Errorf("Expected the changed file read again, got %q", episodes[1].Title)
+	}
+	for _, episode := range episodes {
+		if len(episode.Enclosures) != 0 {
+			t.Errorf("Expected no enclosures for local file %q, got %v", episode.Title, episode.Enclosures)
+		}
+	}
+}
+
+func TestFetch_LocalFolderMissing(t *testing.T) {
+	feedURL, _ := LocalFeedURL(filepath.Join(t.TempDir(), "gone"))
+	if _, err := Fetch(feedURL, Options{}); err == nil {
+		t.Error("Expected an error for a missing folder")
+	}
+}
+
+func TestLocalFeedURL(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("No home directory")
+	}
+
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"/srv/My Lectures", "file:///srv/My%20Lectures", true},
+		{"file:///srv/My%20Lectures", "file:///srv/My%20Lectures", true},
+		{"~/Audiobooks", "file://" + filepath.ToSlash(filepath.Join(home, "Audiobooks")), true},
+		{"https://example.com/feed.xml", "", false},
+		{"example.com/feed.xml", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := LocalFeedURL(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("LocalFeedURL(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package feed
+
+import (
+	"log"
+	neturl "net/url"
+	"strings"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// Default limits for following a paginated feed
+const (
+	DefaultMaxHistoryPages    = 50
+	DefaultMaxHistoryEpisodes = 5000
+)
+
+// HistoryLimits bounds how much of a paginated feed FetchHistory follows
+type HistoryLimits struct {
+	// MaxPages is the most pages fetched, including the first; 0 means DefaultMaxHistoryPages
+	MaxPages int
+
+	// MaxEpisodes stops pagination once this many episodes are known; 0 means
+	// DefaultMaxHistoryEpisodes
+	MaxEpisodes int
+}
+
+// nextPageURL returns the absolute URL of the page holding older episodes:
+// RFC 5005 rel="next" for paged feeds, or rel="prev-archive" for archived feeds
+func nextPageURL(links []Link, base *neturl.URL) string {
+	for _, rel := range []string{"next", "prev-archive"} {
+		for _, link := range links {
+			if !strings.EqualFold(strings.TrimSpace(link.Rel), rel) || strings.TrimSpace(link.Href) == "" {
+				continue
+			}
+			ref, err := neturl.Parse(strings.TrimSpace(link.Href))
+			if err != nil {
+				continue
+			}
+			next := base.ResolveReference(ref)
+			if next.String() == base.String() {
+				continue
+			}
+			return next.String()
+		}
+	}
+	return ""
+}
+
+// selfURL returns the absolute URL the feed declares as its own with rel="self"
+func selfURL(links []Link, base *neturl.URL) string {
+	for _, link := range links {
+		if !strings.EqualFold(strings.TrimSpace(link.Rel), "self") || strings.TrimSpace(link.Href) == "" {
+			continue
+		}
+		if ref, err := neturl.Parse(strings.TrimSpace(link.Href)); err == nil {
+			return base.ResolveReference(ref).String()
+		}
+	}
+	return ""
+}
+
+// FetchHistory fetches the feed at url and follows its pagination links, merging
+// the pages into one podcast. A page that fails to load ends pagination early
+// rather than failing the whole fetch; Result.NextPage is then where it stopped.
+func FetchHistory(url string, opts Options, limits HistoryLimits) (*Result, error) {
+	result, err := Fetch(url, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPages := limits.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxHistoryPages
+	}
+	maxEpisodes := limits.MaxEpisodes
+	if maxEpisodes <= 0 {
+		maxEpisodes = DefaultMaxHistoryEpisodes
+	}
+
+	// Later pages generate episode IDs from the feed's URL, not the page's
+	pageOpts := opts
+	if pageOpts.IdentityURL == "" {
+		pageOpts.IdentityURL = url
+	}
+
+	podcast := result.Podcast
+	seenPages := map[string]bool{url: true}
+	seenEpisodes := make(map[string]bool, len(podcast.Episodes))
+	for _, episode := range podcast.Episodes {
+		seenEpisodes[episode.ID] = true
+	}
+
+	next := result.NextPage
+	for next != "" && result.Pages < maxPages && len(podcast.Episodes) < maxEpisodes {
+		logNext := credentials.RedactURL(next)
+		if seenPages[next] {
+			log.Printf("Feed parser: Pagination of %s loops back to %s, stopping", credentials.RedactURL(url), logNext)
+			next = ""
+			break
+		}
+		seenPages[next] = true
+
+		// Never send the feed's credentials to another host
+		pageOpts.Credential = opts.Credential
+		if opts.Credential != nil && !credentials.SameHost(url, next) && !opts.Credential.Allows(next) {
+			pageOpts.Credential = nil
+		}
+
+		page, err := Fetch(next, pageOpts)
+		if err != nil {
+			log.Printf("Feed parser: Stopping pagination of %s at %s: %v", credentials.RedactURL(url), logNext, err)
+			break
+		}
+		result.Pages++
+
+		for _, episode := range page.Podcast.Episodes {
+			if !seenEpisodes[episode.ID] {
+				seenEpisodes[episode.ID] = true
+				podcast.Episodes = append(podcast.Episodes, episode)
+			}
+		}
+		podcast.ParseIssues = mergeParseIssues(podcast.ParseIssues, page.Podcast.ParseIssues)
+		next = page.NextPage
+	}
+
+	if len(podcast.Episodes) > maxEpisodes {
+		podcast.Episodes = podcast.Episodes[:maxEpisodes]
+	}
+	result.NextPage = next
+
+	log.Printf("Feed parser: Fetched %d pages with %d episodes from %s", result.Pages, len(podcast.Episodes), credentials.RedactURL(url))
+	return result, nil
+}
+
+// mergeParseIssues combines the parse issues of two pages
+func mergeParseIssues(a, b *models.ParseIssues) *models.ParseIssues {
+	if b.Total() == 0 {
+		return a
+	}
+	if a.Total() == 0 {
+		return b
+	}
+	merged := *a
+	merged.Dates += b.Dates
+	merged.Durations += b.Durations
+	if merged.DateSample == "" {
+		merged.DateSample = b.DateSample
+	}
+	if merged.DurationSample == "" {
+		merged.DurationSample = b.DurationSample
+	}
+	return &merged
+}
@@ -0,0 +1,167 @@
+package feed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// pagedFeed returns a feed page with the given episodes and atom links
+func pagedFeed(episodes []string, links ...string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>Paged Podcast</title>
+    <link>https://example.com/</link>
+`)
+	for _, link := range links {
+		b.WriteString("    " + link + "\n")
+	}
+	for _, episode := range episodes {
+		fmt.Fprintf(&b, `    <item>
+      <title>%s</title>
+      <enclosure url="https://example.com/%s.mp3" type="audio/mpeg"/>
+      <pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate>
+    </item>
+`, episode, episode)
+	}
+	b.WriteString("  </channel>\n</rss>")
+	return b.String()
+}
+
+func TestFetchHistory_FollowsPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Write([]byte(pagedFeed([]string{"ep5", "ep4"},
+				`<atom:link rel="self" href="/feed"/>`,
+				`<atom:link rel="next" href="/feed?page=2"/>`)))
+		case "2":
+			// Overlaps with the first page
+			w.Write([]byte(pagedFeed([]string{"ep4", "ep3", "ep2"},
+				`<atom:link rel="next" href="`+server.URL+`/feed?page=3"/>`)))
+		case "3":
+			w.Write([]byte(pagedFeed([]string{"ep1"},
+				`<atom:link rel="prev-archive" href="/feed?page=2"/>`)))
+		}
+	}))
+	defer server.Close()
+
+	result, err := FetchHistory(server.URL+"/feed", Options{}, HistoryLimits{})
+	if err != nil {
+		t.Fatalf("Failed to fetch history: %v", err)
+	}
+
+	var titles []string
+	for _, episode := range result.Podcast.Episodes {
+		titles = append(titles, episode.Title)
+	}
+	if got := strings.Join(titles, ","); got != "ep5,ep4,ep3,ep2,ep1" {
+		t.Errorf("Expected pages merged newest first without duplicates, got %s", got)
+	}
+	if result.Pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", result.Pages)
+	}
+	if result.NextPage != "" {
+		t.Errorf("Expected pagination to stop at the loop back to page 2, got next page %s", result.NextPage)
+	}
+	if !result.Podcast.Paginated {
+		t.Error("Expected podcast to be marked as paginated")
+	}
+
+	// Episodes from later pages get the same IDs as if they were on the first page
+	feedURL := server.URL + "/feed"
+	ep1 := result.Podcast.Episodes[4]
+	if want := models.GenerateEpisodeID(feedURL, ep1.URL, ep1.PublishDate); ep1.ID != want {
+		t.Errorf("Expected episode ID based on the feed URL, got %s want %s", ep1.ID, want)
+	}
+}
+
+func TestFetchHistory_Limits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+		w.Write([]byte(pagedFeed(
+			[]string{fmt.Sprintf("p%da", page), fmt.Sprintf("p%db", page)},
+			fmt.Sprintf(`<atom:link rel="next" href="/feed?page=%d"/>`, page+1))))
+	}))
+	defer server.Close()
+
+	result, err := FetchHistory(server.URL+"/feed", Options{}, HistoryLimits{MaxPages: 3})
+	if err != nil {
+		t.Fatalf("Failed to fetch history: %v", err)
+	}
+	if result.Pages != 3 || len(result.Podcast.Episodes) != 6 {
+		t.Errorf("Expected 3 pages and 6 episodes, got %d pages and %d episodes", result.Pages, len(result.Podcast.Episodes))
+	}
+	if !strings.HasSuffix(result.NextPage, "page=4") {
+		t.Errorf("Expected remaining page to be reported, got %q", result.NextPage)
+	}
+
+	result, err = FetchHistory(server.URL+"/feed", Options{}, HistoryLimits{MaxEpisodes: 5})
+	if err != nil {
+		t.Fatalf("Failed to fetch history: %v", err)
+	}
+	if len(result.Podcast.Episodes) != 5 {
+		t.Errorf("Expected episodes capped at 5, got %d", len(result.Podcast.Episodes))
+	}
+}
+
+func TestFetchHistory_FailedPageKeepsEarlierPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(pagedFeed([]string{"ep2", "ep1"}, `<atom:link rel="next" href="?page=2"/>`)))
+	}))
+	defer server.Close()
+
+	result, err := FetchHistory(server.URL+"/feed", Options{}, HistoryLimits{})
+	if err != nil {
+		t.Fatalf("Expected a failed later page not to fail the fetch: %v", err)
+	}
+	if len(result.Podcast.Episodes) != 2 || result.Pages != 1 {
+		t.Errorf("Expected the first page only, got %d pages", result.Pages)
+	}
+	if result.NextPage == "" {
+		t.Error("Expected the failed page to be reported as remaining")
+	}
+}
+
+func TestFetchHistory_CredentialsNotSentToOtherHosts(t *testing.T) {
+	var leaked bool
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			leaked = true
+		}
+		w.Write([]byte(pagedFeed([]string{"ep1"})))
+	}))
+	defer other.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(pagedFeed([]string{"ep2"}, `<atom:link rel="next" href="`+other.URL+`/archive"/>`)))
+	}))
+	defer server.Close()
+
+	// Both test servers listen on 127.0.0.1, so reach the feed by another name
+	feedURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/feed"
+	cred := &credentials.Credential{Username: "user", Password: "secret"}
+	result, err := FetchHistory(feedURL, Options{Credential: cred}, HistoryLimits{})
+	if err != nil {
+		t.Fatalf("Failed to fetch history: %v", err)
+	}
+	if len(result.Podcast.Episodes) != 2 {
+		t.Errorf("Expected both pages, got %d episodes", len(result.Podcast.Episodes))
+	}
+	if leaked {
+		t.Error("Credentials were sent to another host")
+	}
+}
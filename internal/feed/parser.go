@@ -1,17 +1,20 @@
 package feed
 
 import (
+	"bufio"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/csams/podcast-tui/internal/credentials"
 	"github.com/csams/podcast-tui/internal/markdown"
 	"github.com/csams/podcast-tui/internal/models"
+	"github.com/csams/podcast-tui/internal/privacy"
 )
 
 type RSS struct {
@@ -22,12 +25,21 @@ type RSS struct {
 type Channel struct {
 	Title       string `xml:"title"`
 	Description string `xml:"description"`
-	Link        string `xml:"link"`
+	Links       []Link `xml:"link"`
 	Image       Image  `xml:"image"`
 	ITunesImage ITunesImage `xml:"itunes:image"`
+	NewFeedURL  string `xml:"new-feed-url"`
 	Items       []Item `xml:"item"`
 }
 
+// Link is a channel <link>: the website for plain RSS links, or an <atom:link>
+// with a relation such as "self" or "next"
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
 type ITunesImage struct {
 	Href string `xml:"href,attr"`
 }
@@ -37,12 +49,18 @@ type Image struct {
 }
 
 type Item struct {
+	GUID          string    `xml:"guid"`
 	Title         string    `xml:"title"`
 	Description   string    `xml:"description"`
-	Enclosure     Enclosure `xml:"enclosure"`
+	Enclosures    []Enclosure `xml:"enclosure"`
 	PubDate       string    `xml:"pubDate"`
 	ITunesDuration string   `xml:"itunes:duration"`
 	Duration      string    `xml:"duration"`
+
+	// Other formats and qualities the episode is offered in
+	AlternateEnclosures []AlternateEnclosure `xml:"alternateEnclosure"`
+	MediaContents       []MediaContent       `xml:"content"`
+	MediaGroups         []MediaGroup         `xml:"group"`
 }
 
 type Enclosure struct {
@@ -51,74 +69,283 @@ type Enclosure struct {
 	Length string `xml:"length,attr"`
 }
 
+// HTTPError is returned when the feed server responds with a non-OK status
+type HTTPError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("server returned status %d for %s", e.StatusCode, e.URL)
+}
+
+// StatusCode returns the HTTP status carried by err, or 0 if it isn't an HTTP error
+func StatusCode(err error) int {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+// Options controls how a feed is fetched
+type Options struct {
+	// IdentityURL is used instead of the fetched URL when generating episode IDs,
+	// so episodes keep their IDs after a feed moves
+	IdentityURL string
+
+	// Credential authenticates requests for private feeds
+	Credential *credentials.Credential
+
+	// MaxBytes limits the size of the feed after decompression; 0 means DefaultMaxFeedBytes
+	MaxBytes int64
+
+	// ETag and LastModified are the validators of the previous fetch. When either
+	// is set the request is conditional, see Result.NotModified.
+	ETag         string
+	LastModified string
+
+	// UserAgent identifies the app to the server; empty means privacy.DefaultUserAgent
+	UserAgent string
+}
+
+// Result is a parsed feed along with where it now lives
+type Result struct {
+	Podcast *models.Podcast
+
+	// MovedTo is set when every redirect followed was permanent (301/308)
+	MovedTo string
+
+	// NewFeedURL is the feed's <itunes:new-feed-url>, if it differs from the fetched URL
+	NewFeedURL string
+
+	// NextPage is the absolute URL of the next, older page of a paginated feed
+	NextPage string
+
+	// Pages is the number of feed pages merged into Podcast
+	Pages int
+
+	// NotModified is set, and Podcast is nil, when a conditional fetch found the
+	// feed unchanged
+	NotModified bool
+}
+
+// NewLocation returns the URL the feed should be fetched from in future, or ""
+// if it hasn't moved. An explicit new-feed-url takes precedence over redirects.
+func (r *Result) NewLocation() string {
+	if r.NewFeedURL != "" {
+		return r.NewFeedURL
+	}
+	return r.MovedTo
+}
+
+// maxRedirects matches the default limit of net/http
+const maxRedirects = 10
+
 func ParseFeed(url string) (*models.Podcast, error) {
-	// Create custom HTTP client with Firefox user agent
+	result, err := Fetch(url, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return result.Podcast, nil
+}
+
+// webSource fetches RSS and Atom feeds over HTTP
+type webSource struct{}
+
+func (webSource) Name() string {
+	return "web feed"
+}
+
+func (webSource) Resolve(input string) (string, bool) {
+	lower := strings.ToLower(input)
+	return input, strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func (webSource) Fetch(url string, opts Options) (*Result, error) {
+	return fetchWeb(url, opts)
+}
+
+// Refresh sends the podcast's validators, so an unchanged feed isn't downloaded again
+func (webSource) Refresh(podcast *models.Podcast, opts Options) (*Result, error) {
+	if opts.ETag == "" && opts.LastModified == "" {
+		opts.ETag = podcast.ETag
+		opts.LastModified = podcast.LastModified
+	}
+	return fetchWeb(podcast.URL, opts)
+}
+
+func (webSource) PlaybackLocation(episodeURL string) (string, bool) {
+	return episodeURL, false
+}
+
+func (webSource) Capabilities() Capabilities {
+	return Capabilities{Downloads: true, History: true, Conditional: true}
+}
+
+// fetchWeb downloads and parses the feed at url, reporting permanent moves
+func fetchWeb(url string, opts Options) (*Result, error) {
+	// Track whether every redirect on the way is permanent
+	permanent := true
 	client := &http.Client{
 		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.Response != nil {
+				status := req.Response.StatusCode
+				if status != http.StatusMovedPermanently && status != http.StatusPermanentRedirect {
+					permanent = false
+				}
+			}
+			if opts.Credential != nil {
+				return opts.Credential.CheckRedirect(req, via)
+			}
+			return nil
+		},
 	}
+
+	// Never log credentials that may be embedded in the URL
+	logURL := credentials.RedactURL(url)
 	
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Printf("Feed parser: Failed to create request for URL %s: %v", url, err)
-		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+		log.Printf("Feed parser: Failed to create request for URL %s: %v", logURL, err)
+		return nil, fmt.Errorf("failed to create request for %s: %w", logURL, err)
 	}
 	
-	// Set Firefox user agent
-	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux i686; rv:141.0) Gecko/20100101 Firefox/141.0")
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = privacy.DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	if opts.Credential != nil {
+		opts.Credential.Apply(req)
+	}
+	if opts.ETag != "" {
+		req.Header.Set("If-None-Match", opts.ETag)
+	}
+	if opts.LastModified != "" {
+		req.Header.Set("If-Modified-Since", opts.LastModified)
+	}
 	
-	log.Printf("Feed parser: Fetching feed from %s", url)
+	log.Printf("Feed parser: Fetching feed from %s", logURL)
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Feed parser: HTTP request failed for %s: %v", url, err)
-		return nil, fmt.Errorf("failed to fetch feed from %s: %w", url, err)
+		log.Printf("Feed parser: HTTP request failed for %s: %v", logURL, err)
+		return nil, fmt.Errorf("failed to fetch feed from %s: %w", logURL, err)
 	}
 	defer resp.Body.Close()
 	
 	// Log response details
 	log.Printf("Feed parser: Response for %s - Status: %s, Content-Type: %s, Content-Length: %s",
-		url, resp.Status, resp.Header.Get("Content-Type"), resp.Header.Get("Content-Length"))
+		logURL, resp.Status, resp.Header.Get("Content-Type"), resp.Header.Get("Content-Length"))
 	
+	if resp.StatusCode == http.StatusNotModified && (opts.ETag != "" || opts.LastModified != "") {
+		log.Printf("Feed parser: %s not modified", logURL)
+		return &Result{NotModified: true}, nil
+	}
+
 	// Check for non-200 status codes
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("Feed parser: Non-OK status code %d for %s", resp.StatusCode, url)
-		return nil, fmt.Errorf("server returned status %d for %s", resp.StatusCode, url)
+		log.Printf("Feed parser: Non-OK status code %d for %s", resp.StatusCode, logURL)
+		return nil, &HTTPError{StatusCode: resp.StatusCode, URL: logURL}
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxFeedBytes
+	}
+	if resp.ContentLength > maxBytes && resp.Header.Get("Content-Encoding") == "" {
+		log.Printf("Feed parser: Feed %s is %d bytes, over the %d byte limit", logURL, resp.ContentLength, maxBytes)
+		return nil, fmt.Errorf("%w of %d bytes: %s", ErrFeedTooLarge, maxBytes, logURL)
+	}
+
+	body, err := decompressBody(resp)
 	if err != nil {
-		log.Printf("Feed parser: Failed to read response body for %s: %v", url, err)
-		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+		log.Printf("Feed parser: Failed to decompress response body for %s: %v", logURL, err)
+		return nil, fmt.Errorf("failed to read response from %s: %w", logURL, err)
 	}
-	
-	log.Printf("Feed parser: Read %d bytes from %s", len(data), url)
+	defer body.Close()
 
-	var rss RSS
-	if err := xml.Unmarshal(data, &rss); err != nil {
-		// Log first 500 bytes of response for debugging
-		sample := string(data)
-		if len(sample) > 500 {
-			sample = sample[:500] + "..."
+	limited := &limitedReader{r: body, max: maxBytes}
+	buffered := bufio.NewReaderSize(limited, htmlSniffSize)
+
+	// A web page instead of a feed, such as a show's homepage
+	if looksLikeHTML(buffered) {
+		page, err := io.ReadAll(buffered)
+		if err != nil {
+			log.Printf("Feed parser: Failed to read web page %s: %v", logURL, err)
+			return nil, fmt.Errorf("failed to read response from %s: %w", logURL, err)
+		}
+		feeds := discoverFeeds(string(page), resp.Request.URL)
+		log.Printf("Feed parser: %s is a web page advertising %d feeds", logURL, len(feeds))
+		return nil, &NotFeedError{URL: logURL, Feeds: feeds}
+	}
+
+	// Stream the feed through the decoder, keeping the start of it for debugging
+	sample := &sampleWriter{}
+	decoder, err := newFeedDecoder(io.TeeReader(buffered, sample), resp.Header.Get("Content-Type"))
+	if err != nil {
+		log.Printf("Feed parser: Failed to decode response body for %s: %v", logURL, err)
+		return nil, fmt.Errorf("failed to read response from %s: %w", logURL, err)
+	}
+
+	channel, format, err := decodeFeed(decoder)
+	if err != nil {
+		if errors.Is(err, ErrFeedTooLarge) {
+			log.Printf("Feed parser: Feed %s exceeded the %d byte limit", logURL, maxBytes)
+			return nil, fmt.Errorf("failed to read response from %s: %w", logURL, err)
 		}
-		log.Printf("Feed parser: XML parsing failed for %s: %v\nFirst 500 bytes: %s", url, err, sample)
-		return nil, fmt.Errorf("failed to parse RSS from %s: %w", url, err)
+		// Log first 500 bytes of response for debugging
+		log.Printf("Feed parser: XML parsing failed for %s: %v\nFirst 500 bytes: %s", logURL, err, sample)
+		return nil, fmt.Errorf("failed to parse %s from %s: %w", format, logURL, err)
 	}
 
+	log.Printf("Feed parser: Read %d bytes from %s", limited.read, logURL)
+
 	// Try to get the best image URL
-	imageURL := rss.Channel.Image.URL
-	if imageURL == "" && rss.Channel.ITunesImage.Href != "" {
-		imageURL = rss.Channel.ITunesImage.Href
+	imageURL := channel.Image.URL
+	if imageURL == "" && channel.ITunesImage.Href != "" {
+		imageURL = channel.ITunesImage.Href
 	}
 	
 	// Create markdown converter
 	converter := markdown.NewMarkdownConverter()
 	
+	result := &Result{}
+	if finalURL := resp.Request.URL.String(); finalURL != url && permanent {
+		log.Printf("Feed parser: %s permanently redirected to %s", logURL, credentials.RedactURL(finalURL))
+		result.MovedTo = finalURL
+	}
+	if newFeedURL := strings.TrimSpace(channel.NewFeedURL); newFeedURL != "" && newFeedURL != url {
+		log.Printf("Feed parser: %s declares new feed URL %s", logURL, credentials.RedactURL(newFeedURL))
+		result.NewFeedURL = newFeedURL
+	}
+
+	if next := nextPageURL(channel.Links, resp.Request.URL); next != "" {
+		log.Printf("Feed parser: %s links to older page %s", logURL, credentials.RedactURL(next))
+		result.NextPage = next
+	}
+
+	idURL := url
+	if opts.IdentityURL != "" {
+		idURL = opts.IdentityURL
+	}
+
 	podcast := &models.Podcast{
-		Title:       rss.Channel.Title,
-		Description: rss.Channel.Description,
-		URL:         url,
-		ImageURL:    imageURL,
-		LastUpdated: time.Now(),
-		Episodes:    make([]*models.Episode, 0, len(rss.Channel.Items)),
+		Title:        channel.Title,
+		Description:  channel.Description,
+		URL:          url,
+		ImageURL:     imageURL,
+		LastUpdated:  time.Now(),
+		SelfURL:      selfURL(channel.Links, resp.Request.URL),
+		Paginated:    result.NextPage != "",
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Episodes:     make([]*models.Episode, 0, len(channel.Items)),
 	}
 	
 	// Convert podcast description
@@ -127,7 +354,8 @@ func ParseFeed(url string) (*models.Podcast, error) {
 		podcast.ConvertedDescription = result.Text
 	}
 
-	for _, item := range rss.Channel.Items {
+	issues := &models.ParseIssues{}
+	for _, item := range channel.Items {
 		// Try to get duration from iTunes namespace first, then fallback to plain duration
 		duration := item.ITunesDuration
 		if duration == "" {
@@ -135,21 +363,37 @@ func ParseFeed(url string) (*models.Podcast, error) {
 		}
 		
 		episode := &models.Episode{
+			GUID:        strings.TrimSpace(item.GUID),
 			Title:       item.Title,
 			Description: item.Description,
-			URL:         item.Enclosure.URL,
-			Duration:    parseDuration(duration),
 		}
 
-		if pubDate, err := parseRFC2822Date(item.PubDate); err == nil {
+		// Items without media are kept, so announcements and show notes still appear
+		enclosures := item.enclosures()
+		switch {
+		case len(enclosures) == 0:
+			episode.TextOnly = true
+		case len(enclosures) > 1:
+			episode.Enclosures = enclosures
+			fallthrough
+		default:
+			episode.URL = enclosures[0].URL
+		}
+
+		if parsed, err := parseDuration(duration); err == nil {
+			episode.Duration = parsed
+		} else {
+			issues.AddDuration(duration)
+		}
+
+		if pubDate, err := parseDate(item.PubDate); err == nil {
 			episode.PublishDate = pubDate
-		} else if item.PubDate != "" {
-			log.Printf("Feed parser: Warning - Failed to parse date '%s' for episode '%s' in feed %s: %v",
-				item.PubDate, item.Title, url, err)
+		} else if strings.TrimSpace(item.PubDate) != "" {
+			issues.AddDate(item.PubDate)
 		}
 
 		// Generate unique ID for the episode
-		episode.GenerateID(url)
+		episode.GenerateID(idURL)
 		
 		// Convert episode description
 		if episode.Description != "" {
@@ -160,86 +404,25 @@ func ParseFeed(url string) (*models.Podcast, error) {
 		podcast.Episodes = append(podcast.Episodes, episode)
 	}
 
+	if issues.Total() > 0 {
+		podcast.ParseIssues = issues
+		log.Printf("Feed parser: Warning - %d unparseable dates (e.g. %q) and %d unparseable durations (e.g. %q) in feed %s",
+			issues.Dates, issues.DateSample, issues.Durations, issues.DurationSample, logURL)
+	}
+
 	// Log successful parsing
 	log.Printf("Feed parser: Successfully parsed feed from %s - Title: %s, Episodes: %d",
-		url, podcast.Title, len(podcast.Episodes))
+		logURL, podcast.Title, len(podcast.Episodes))
 	
 	// Warn about potential issues
 	if podcast.Title == "" {
-		log.Printf("Feed parser: Warning - Empty title for feed %s", url)
+		log.Printf("Feed parser: Warning - Empty title for feed %s", logURL)
 	}
 	if len(podcast.Episodes) == 0 {
-		log.Printf("Feed parser: Warning - No episodes found in feed %s", url)
-	}
-	
-	return podcast, nil
-}
-
-func parseRFC2822Date(dateStr string) (time.Time, error) {
-	layouts := []string{
-		time.RFC1123Z,
-		time.RFC1123,
-		"Mon, 02 Jan 2006 15:04:05 -0700",
-		"Mon, 2 Jan 2006 15:04:05 -0700",
-	}
-
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, dateStr); err == nil {
-			return t, nil
-		}
-	}
-
-	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
-}
-
-// parseDuration converts various duration formats to time.Duration
-func parseDuration(duration string) time.Duration {
-	if duration == "" {
-		return 0
-	}
-	
-	// Try to parse as seconds first (most common case)
-	if seconds, err := strconv.Atoi(duration); err == nil {
-		return time.Duration(seconds) * time.Second
-	}
-	
-	// Try to parse as HH:MM:SS or MM:SS format
-	if strings.Contains(duration, ":") {
-		return parseTimeFormatDuration(duration)
-	}
-	
-	// If we can't parse it, return 0
-	return 0
-}
-
-// parseTimeFormatDuration parses HH:MM:SS or MM:SS format into time.Duration
-func parseTimeFormatDuration(timeStr string) time.Duration {
-	parts := strings.Split(timeStr, ":")
-	
-	var hours, minutes, seconds int
-	var err error
-	
-	switch len(parts) {
-	case 2: // MM:SS format
-		if minutes, err = strconv.Atoi(parts[0]); err != nil {
-			return 0
-		}
-		if seconds, err = strconv.Atoi(parts[1]); err != nil {
-			return 0
-		}
-	case 3: // HH:MM:SS format
-		if hours, err = strconv.Atoi(parts[0]); err != nil {
-			return 0
-		}
-		if minutes, err = strconv.Atoi(parts[1]); err != nil {
-			return 0
-		}
-		if seconds, err = strconv.Atoi(parts[2]); err != nil {
-			return 0
-		}
-	default:
-		return 0
+		log.Printf("Feed parser: Warning - No episodes found in feed %s", logURL)
 	}
 	
-	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	result.Podcast = podcast
+	result.Pages = 1
+	return result, nil
 }
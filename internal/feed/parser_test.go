@@ -1,12 +1,16 @@
 package feed
 
 import (
+	"bytes"
 	"fmt"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/csams/podcast-tui/internal/credentials"
 )
 
 func TestParseFeed_Success(t *testing.T) {
@@ -301,6 +305,10 @@ func TestParseFeed_ServerError(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for server error response")
 	}
+
+	if status := StatusCode(err); status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, status)
+	}
 }
 
 func TestParseFeed_InvalidXML(t *testing.T) {
@@ -447,7 +455,7 @@ func TestParseFeed_DateFormats(t *testing.T) {
 	}
 }
 
-func TestParseRFC2822Date(t *testing.T) {
+func TestParseDate(t *testing.T) {
 	// Test the internal date parsing function
 	testCases := []struct {
 		input    string
@@ -472,7 +480,7 @@ func TestParseRFC2822Date(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		result, err := parseRFC2822Date(tc.input)
+		result, err := parseDate(tc.input)
 
 		if tc.hasError {
 			if err == nil {
@@ -565,3 +573,308 @@ func TestParseFeed_IDUniquenessAcrossFeeds(t *testing.T) {
 		t.Error("Both episodes should have generated IDs")
 	}
 }
+
+// locationTestFeed is a minimal feed used by the redirect and move tests
+const locationTestFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Moving Podcast</title>
+    %s
+    <item>
+      <title>Episode 1</title>
+      <enclosure url="https://example.com/episode1.mp3" type="audio/mpeg" length="1024"/>
+      <pubDate>Mon, 16 Oct 2023 12:00:00 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+func newLocationTestServer(redirectStatus int, extra string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", redirectStatus)
+		case "/new", "/direct":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprintf(w, locationTestFeed, extra)
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestFetch_PermanentRedirect(t *testing.T) {
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusPermanentRedirect} {
+		t.Run(fmt.Sprintf("status %d", status), func(t *testing.T) {
+			server := newLocationTestServer(status, "")
+			defer server.Close()
+
+			result, err := Fetch(server.URL+"/old", Options{})
+			if err != nil {
+				t.Fatalf("Failed to fetch feed: %v", err)
+			}
+			if result.MovedTo != server.URL+"/new" {
+				t.Errorf("Expected feed to have moved to %s, got %q", server.URL+"/new", result.MovedTo)
+			}
+			if result.NewLocation() != server.URL+"/new" {
+				t.Errorf("Expected new location %s, got %q", server.URL+"/new", result.NewLocation())
+			}
+			if result.Podcast.URL != server.URL+"/old" {
+				t.Errorf("Expected podcast URL to stay the requested URL, got %s", result.Podcast.URL)
+			}
+		})
+	}
+}
+
+func TestFetch_TemporaryRedirect(t *testing.T) {
+	for _, status := range []int{http.StatusFound, http.StatusTemporaryRedirect} {
+		t.Run(fmt.Sprintf("status %d", status), func(t *testing.T) {
+			server := newLocationTestServer(status, "")
+			defer server.Close()
+
+			result, err := Fetch(server.URL+"/old", Options{})
+			if err != nil {
+				t.Fatalf("Failed to fetch feed: %v", err)
+			}
+			if result.NewLocation() != "" {
+				t.Errorf("Expected temporary redirect not to move the feed, got %q", result.NewLocation())
+			}
+		})
+	}
+}
+
+func TestFetch_NewFeedURL(t *testing.T) {
+	server := newLocationTestServer(http.StatusMovedPermanently,
+		"<itunes:new-feed-url>https://feeds.example.org/moved.xml</itunes:new-feed-url>")
+	defer server.Close()
+
+	// The declared URL wins over the redirect target
+	result, err := Fetch(server.URL+"/old", Options{})
+	if err != nil {
+		t.Fatalf("Failed to fetch feed: %v", err)
+	}
+	if result.NewFeedURL != "https://feeds.example.org/moved.xml" {
+		t.Errorf("Expected new feed URL to be parsed, got %q", result.NewFeedURL)
+	}
+	if result.NewLocation() != "https://feeds.example.org/moved.xml" {
+		t.Errorf("Expected new-feed-url to take precedence, got %q", result.NewLocation())
+	}
+}
+
+func TestFetch_SelfLinkAndGUIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<rss xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+			<title>Show</title>
+			<atom:link rel="self" type="application/rss+xml" href="/canonical.xml"/>
+			<item><guid isPermaLink="false"> show-ep-1 </guid><title>One</title><enclosure url="https://example.com/1.mp3"/></item>
+			<item><title>Two</title><enclosure url="https://example.com/2.mp3"/></item>
+		</channel></rss>`))
+	}))
+	defer server.Close()
+
+	result, err := Fetch(server.URL+"/feed?utm_source=web", Options{})
+	if err != nil {
+		t.Fatalf("Failed to fetch feed: %v", err)
+	}
+	if result.Podcast.SelfURL != server.URL+"/canonical.xml" {
+		t.Errorf("Expected the resolved self link, got %q", result.Podcast.SelfURL)
+	}
+	if result.Podcast.Episodes[0].GUID != "show-ep-1" || result.Podcast.Episodes[1].GUID != "" {
+		t.Errorf("Expected GUIDs to be parsed, got %q and %q",
+			result.Podcast.Episodes[0].GUID, result.Podcast.Episodes[1].GUID)
+	}
+}
+
+func TestFetch_IdentityURLPreservesEpisodeIDs(t *testing.T) {
+	server := newLocationTestServer(http.StatusMovedPermanently, "")
+	defer server.Close()
+
+	original, err := ParseFeed(server.URL + "/direct")
+	if err != nil {
+		t.Fatalf("Failed to parse feed: %v", err)
+	}
+
+	moved, err := Fetch(server.URL+"/new", Options{IdentityURL: server.URL + "/direct"})
+	if err != nil {
+		t.Fatalf("Failed to fetch moved feed: %v", err)
+	}
+
+	if original.Episodes[0].ID != moved.Podcast.Episodes[0].ID {
+		t.Errorf("Expected episode IDs to be preserved, got %s and %s",
+			original.Episodes[0].ID, moved.Podcast.Episodes[0].ID)
+	}
+}
+
+func TestFetch_Gone(t *testing.T) {
+	server := newLocationTestServer(http.StatusMovedPermanently, "")
+	defer server.Close()
+
+	_, err := Fetch(server.URL+"/gone", Options{})
+	if StatusCode(err) != http.StatusGone {
+		t.Errorf("Expected status %d, got %v", http.StatusGone, err)
+	}
+}
+
+func TestFetch_Credentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "secret" || r.Header.Get("X-Api-Key") != "abc123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, locationTestFeed, "")
+	}))
+	defer server.Close()
+
+	_, err := Fetch(server.URL, Options{})
+	if StatusCode(err) != http.StatusUnauthorized {
+		t.Fatalf("Expected unauthenticated fetch to fail with 401, got %v", err)
+	}
+
+	store, err := credentials.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Set(server.URL, &credentials.Credential{
+		Username: "user",
+		Password: "secret",
+		Headers:  map[string]string{"X-Api-Key": "abc123"},
+	})
+
+	if _, err := Fetch(server.URL, Options{Credential: store.Get(server.URL)}); err != nil {
+		t.Errorf("Expected authenticated fetch to succeed, got %v", err)
+	}
+}
+
+func TestFetch_CredentialsNotSentToOtherHosts(t *testing.T) {
+	var leaked bool
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Api-Key") != "" {
+			leaked = true
+		}
+		fmt.Fprintf(w, locationTestFeed, "")
+	}))
+	defer other.Close()
+
+	// Redirect from 127.0.0.1 to localhost, which is a different host name
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, otherURL+"/feed.xml", http.StatusFound)
+	}))
+	defer server.Close()
+
+	store, err := credentials.Load(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Set(server.URL, &credentials.Credential{
+		Username: "user",
+		Password: "secret",
+		Headers:  map[string]string{"X-Api-Key": "abc123"},
+	})
+
+	if _, err := Fetch(server.URL, Options{Credential: store.Get(server.URL)}); err != nil {
+		t.Fatalf("Failed to fetch feed: %v", err)
+	}
+	if leaked {
+		t.Error("Expected credentials not to be forwarded to another host")
+	}
+}
+
+func TestFetch_CredentialsNotLogged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, locationTestFeed, "")
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	if _, err := Fetch(server.URL+"/feed.xml?auth=hunter2&key=abc123", Options{}); err != nil {
+		t.Fatalf("Failed to fetch feed: %v", err)
+	}
+	if strings.Contains(logs.String(), "hunter2") || strings.Contains(logs.String(), "abc123") {
+		t.Errorf("Expected the credentials in the URL to be redacted, logged:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "Successfully parsed feed") {
+		t.Errorf("Expected the fetch to be logged, logged:\n%s", logs.String())
+	}
+}
+
+func TestFetch_Enclosures(t *testing.T) {
+	rssContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:podcast="https://podcastindex.org/namespace/1.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Options</title>
+    <item>
+      <guid>ep-2</guid>
+      <title>Two Formats</title>
+      <enclosure url="https://example.com/2.mp3" type="audio/mpeg" length="40000000"/>
+      <podcast:alternateEnclosure type="audio/mpeg" length="40000000" bitrate="128000" default="true">
+        <podcast:source uri="https://example.com/2.mp3"/>
+      </podcast:alternateEnclosure>
+      <podcast:alternateEnclosure type="audio/opus" length="10000000" bitrate="32000" lang="en" title="Low bandwidth">
+        <podcast:source uri="ipfs://QmExample"/>
+        <podcast:source uri="https://example.com/2.opus"/>
+      </podcast:alternateEnclosure>
+      <media:group>
+        <media:content url="https://example.com/2.mp4" type="video/mp4" fileSize="900000000" bitrate="2500"/>
+        <media:content url="https://example.com/2.jpg" medium="image"/>
+      </media:group>
+      <pubDate>Tue, 16 Oct 2023 12:00:00 GMT</pubDate>
+    </item>
+    <item>
+      <guid>note-1</guid>
+      <title>Announcement</title>
+      <description>No audio this week</description>
+      <pubDate>Mon, 15 Oct 2023 12:00:00 GMT</pubDate>
+    </item>
+    <item>
+      <guid>note-2</guid>
+      <title>Another Announcement</title>
+      <pubDate>Mon, 15 Oct 2023 12:00:00 GMT</pubDate>
+    </item>
+  </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(rssContent))
+	}))
+	defer server.Close()
+
+	result, err := Fetch(server.URL, Options{})
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	episodes := result.Podcast.Episodes
+	if len(episodes) != 3 {
+		t.Fatalf("Expected 3 episodes, got %d", len(episodes))
+	}
+
+	options := episodes[0]
+	if options.URL != "https://example.com/2.mp3" || !options.Playable() {
+		t.Errorf("Expected the feed's enclosure to be used, got %q", options.URL)
+	}
+	if len(options.Enclosures) != 3 {
+		t.Fatalf("Expected 3 alternatives, got %+v", options.Enclosures)
+	}
+	if mp3 := options.Enclosures[0]; mp3.Bitrate != 128 || mp3.Length != 40000000 {
+		t.Errorf("Expected duplicate descriptions to be combined, got %+v", mp3)
+	}
+	if opus := options.Enclosures[1]; opus.URL != "https://example.com/2.opus" || opus.Bitrate != 32 || opus.Language != "en" || opus.Title != "Low bandwidth" {
+		t.Errorf("Unexpected alternate enclosure %+v", opus)
+	}
+	if video := options.Enclosures[2]; video.Type != "video/mp4" || video.Bitrate != 2500 {
+		t.Errorf("Unexpected media content %+v", video)
+	}
+
+	for _, note := range episodes[1:] {
+		if !note.TextOnly || note.Playable() || note.URL != "" {
+			t.Errorf("Expected %q to be text only, got %+v", note.Title, note)
+		}
+	}
+	if episodes[1].ID == episodes[2].ID {
+		t.Error("Expected text-only episodes on the same day to get different IDs")
+	}
+}
@@ -0,0 +1,153 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strings"
+	"sync"
+
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// ErrUnsupportedSource is returned for URLs no registered source handles
+var ErrUnsupportedSource = errors.New("unsupported podcast source")
+
+// Capabilities describe what a source supports, so callers can adapt without
+// knowing which source a podcast comes from
+type Capabilities struct {
+	// Downloads is set when episodes are remote and can be downloaded for offline play
+	Downloads bool
+
+	// History is set when feeds may link to older pages, see FetchHistory
+	History bool
+
+	// Conditional is set when Refresh can skip unchanged podcasts, see Result.NotModified
+	Conditional bool
+}
+
+// Source fetches podcasts from one kind of location, such as web feeds or
+// folders on disk. Sources are registered for URL schemes with Register.
+type Source interface {
+	// Name describes the source for the user
+	Name() string
+
+	// Resolve returns the URL to subscribe to for what the user entered, or false
+	// if the source doesn't recognise it
+	Resolve(input string) (string, bool)
+
+	// Fetch fetches and parses the podcast at url
+	Fetch(url string, opts Options) (*Result, error)
+
+	// Refresh fetches the podcast again. Sources with conditional refreshes use the
+	// state recorded on podcast by the previous fetch and may return a result with
+	// NotModified set and no Podcast.
+	Refresh(podcast *models.Podcast, opts Options) (*Result, error)
+
+	// PlaybackLocation returns what the player should open for an episode, and
+	// whether that is a file on this machine
+	PlaybackLocation(episodeURL string) (string, bool)
+
+	Capabilities() Capabilities
+}
+
+type registration struct {
+	source  Source
+	schemes []string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []registration
+)
+
+func init() {
+	// Local folders come first so they can claim plain paths before the web
+	// source treats them as URLs
+	Register(localSource{}, "file")
+	Register(webSource{}, "http", "https")
+}
+
+// Register adds a source for URLs with the given schemes. Sources are asked to
+// resolve user input in the order they are registered.
+func Register(source Source, schemes ...string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, registration{source: source, schemes: schemes})
+}
+
+// SourceFor returns the source that handles url
+func SourceFor(url string) (Source, error) {
+	scheme := ""
+	if u, err := neturl.Parse(url); err == nil {
+		scheme = strings.ToLower(u.Scheme)
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, reg := range registry {
+		for _, s := range reg.schemes {
+			if s == scheme {
+				return reg.source, nil
+			}
+		}
+	}
+	if scheme == "" {
+		return nil, fmt.Errorf("%w: %q is not a URL or folder", ErrUnsupportedSource, url)
+	}
+	return nil, fmt.Errorf("%w: %s URLs", ErrUnsupportedSource, scheme)
+}
+
+// Resolve returns the URL to subscribe to for what the user entered: a feed or
+// website URL, or a path. Input no source recognises is returned unchanged, so
+// that fetching it reports a useful error.
+func Resolve(input string) string {
+	input = strings.TrimSpace(input)
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for _, reg := range registry {
+		if url, ok := reg.source.Resolve(input); ok {
+			return url
+		}
+	}
+	return input
+}
+
+// Fetch fetches and parses the podcast at url using the source for its scheme
+func Fetch(url string, opts Options) (*Result, error) {
+	source, err := SourceFor(url)
+	if err != nil {
+		return nil, err
+	}
+	return source.Fetch(url, opts)
+}
+
+// Refresh fetches a subscribed podcast again using the source for its URL
+func Refresh(podcast *models.Podcast, opts Options) (*Result, error) {
+	source, err := SourceFor(podcast.URL)
+	if err != nil {
+		return nil, err
+	}
+	return source.Refresh(podcast, opts)
+}
+
+// CapabilitiesOf returns the capabilities of the source for url, or none if no
+// source handles it
+func CapabilitiesOf(url string) Capabilities {
+	source, err := SourceFor(url)
+	if err != nil {
+		return Capabilities{}
+	}
+	return source.Capabilities()
+}
+
+// PlaybackLocation returns what the player should open for an episode, and
+// whether that is a file on this machine
+func PlaybackLocation(episodeURL string) (string, bool) {
+	source, err := SourceFor(episodeURL)
+	if err != nil {
+		return episodeURL, false
+	}
+	return source.PlaybackLocation(episodeURL)
+}
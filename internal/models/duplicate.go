@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minSharedEpisodes is how many episodes two feeds must have in common, by GUID
+// or enclosure URL, to be considered the same feed on that basis alone
+const minSharedEpisodes = 3
+
+// FindDuplicate returns the subscribed podcast that candidate, a freshly fetched
+// feed, appears to be the same feed as, along with the reason, or nil
+func (s *Subscriptions) FindDuplicate(candidate *Podcast) (*Podcast, string) {
+	for _, p := range s.Podcasts {
+		if p == candidate {
+			continue
+		}
+		if reason := sameFeed(p, candidate); reason != "" {
+			return p, reason
+		}
+	}
+	return nil, ""
+}
+
+// sameFeed describes why two podcasts appear to be the same feed, or returns ""
+func sameFeed(existing, candidate *Podcast) string {
+	for _, url := range append([]string{candidate.URL}, candidate.PreviousURLs...) {
+		if existing.HasURL(url) {
+			return "same feed URL"
+		}
+	}
+	if candidate.SelfURL != "" && (existing.HasURL(candidate.SelfURL) || SameFeedURL(existing.SelfURL, candidate.SelfURL)) {
+		return "same self link"
+	}
+	if SameFeedURL(existing.SelfURL, candidate.URL) {
+		return "same self link"
+	}
+
+	shared := sharedEpisodes(existing, candidate)
+	if shared >= minSharedEpisodes {
+		return fmt.Sprintf("%d episodes in common", shared)
+	}
+	if shared > 0 && sameTitle(existing.Title, candidate.Title) {
+		return "same title and episodes"
+	}
+	return ""
+}
+
+// sharedEpisodes counts the candidate's episodes that existing also has
+func sharedEpisodes(existing, candidate *Podcast) int {
+	guids := make(map[string]bool, len(existing.Episodes))
+	urls := make(map[string]bool, len(existing.Episodes))
+	for _, episode := range existing.Episodes {
+		if episode.GUID != "" {
+			guids[episode.GUID] = true
+		}
+		if episode.URL != "" {
+			urls[feedURLKey(episode.URL)] = true
+		}
+	}
+
+	shared := 0
+	for _, episode := range candidate.Episodes {
+		if (episode.GUID != "" && guids[episode.GUID]) || (episode.URL != "" && urls[feedURLKey(episode.URL)]) {
+			shared++
+		}
+	}
+	return shared
+}
+
+func sameTitle(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	return a != "" && strings.EqualFold(a, b)
+}
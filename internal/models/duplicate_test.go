@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func episodes(urlFormat string, guids ...string) []*Episode {
+	var list []*Episode
+	for i, guid := range guids {
+		list = append(list, &Episode{GUID: guid, URL: fmt.Sprintf(urlFormat, i)})
+	}
+	return list
+}
+
+func TestFindDuplicate(t *testing.T) {
+	existing := &Podcast{
+		Title:    "The Show",
+		URL:      "https://example.com/feed.xml",
+		SelfURL:  "https://feeds.example.com/show",
+		Episodes: episodes("https://cdn.example.com/%d.mp3", "g1", "g2", "g3", "g4"),
+	}
+	other := &Podcast{
+		Title:    "Another Show",
+		URL:      "https://other.example.org/rss",
+		Episodes: episodes("https://cdn.example.org/%d.mp3", "o1", "o2"),
+	}
+	subs := &Subscriptions{Podcasts: []*Podcast{other, existing}}
+
+	tests := []struct {
+		name      string
+		candidate *Podcast
+		want      *Podcast
+		reason    string
+	}{
+		{
+			name:      "equivalent URL",
+			candidate: &Podcast{URL: "http://EXAMPLE.com/feed.xml?utm_source=x"},
+			want:      existing,
+			reason:    "same feed URL",
+		},
+		{
+			name:      "self link names a subscribed URL",
+			candidate: &Podcast{URL: "https://redirect.example.net/abc", SelfURL: "https://example.com/feed.xml"},
+			want:      existing,
+			reason:    "same self link",
+		},
+		{
+			name:      "same self link",
+			candidate: &Podcast{URL: "https://mirror.example.net/show", SelfURL: "https://feeds.example.com/show/"},
+			want:      existing,
+			reason:    "same self link",
+		},
+		{
+			name: "shared GUIDs",
+			candidate: &Podcast{
+				Title:    "The Show (Ad-Free)",
+				URL:      "https://premium.example.com/feed",
+				Episodes: episodes("https://premium.example.com/%d.mp3", "g2", "g3", "g4", "p1"),
+			},
+			want:   existing,
+			reason: "3 episodes in common",
+		},
+		{
+			name: "same title and an enclosure in common",
+			candidate: &Podcast{
+				Title:    " the show ",
+				URL:      "https://mirror.example.net/show.rss",
+				Episodes: episodes("https://cdn.example.com/%d.mp3", ""),
+			},
+			want:   existing,
+			reason: "same title and episodes",
+		},
+		{
+			name: "same title only",
+			candidate: &Podcast{
+				Title:    "The Show",
+				URL:      "https://unrelated.example.net/feed",
+				Episodes: episodes("https://unrelated.example.net/%d.mp3", "u1"),
+			},
+		},
+		{
+			name: "one shared episode",
+			candidate: &Podcast{
+				Title:    "Crossover",
+				URL:      "https://crossover.example.net/feed",
+				Episodes: episodes("https://crossover.example.net/%d.mp3", "g1", "c1", "c2", "c3"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := subs.FindDuplicate(tt.candidate)
+			if got != tt.want || reason != tt.reason {
+				var title string
+				if got != nil {
+					title = got.Title
+				}
+				t.Errorf("Expected %v (%q), got %q (%q)", tt.want != nil, tt.reason, title, reason)
+			}
+		})
+	}
+}
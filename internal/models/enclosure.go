@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Enclosure qualities a podcast may prefer, see Podcast.PreferredQuality
+const (
+	QualityDefault = ""     // The feed's own choice
+	QualityHigh    = "high" // The highest bitrate, or largest file
+	QualityLow     = "low"  // The lowest bitrate, or smallest file
+)
+
+// Enclosure is one media file an episode is offered as. Feeds may offer several
+// alternatives through podcast:alternateEnclosure or Media RSS.
+type Enclosure struct {
+	URL      string `json:"url"`
+	Type     string `json:"type,omitempty"`
+	Length   int64  `json:"length,omitempty"`  // Bytes, 0 if unknown
+	Bitrate  int    `json:"bitrate,omitempty"` // kbit/s, 0 if unknown
+	Language string `json:"language,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+// Label describes the enclosure briefly, such as "audio/mpeg 128k"
+func (e Enclosure) Label() string {
+	var parts []string
+	if e.Title != "" {
+		parts = append(parts, e.Title)
+	}
+	if e.Type != "" {
+		parts = append(parts, e.Type)
+	}
+	if e.Bitrate > 0 {
+		parts = append(parts, fmt.Sprintf("%dk", e.Bitrate))
+	}
+	if e.Language != "" {
+		parts = append(parts, e.Language)
+	}
+	if len(parts) == 0 {
+		return e.URL
+	}
+	return strings.Join(parts, " ")
+}
+
+// Playable reports whether the episode has media to play or download. Text-only
+// items, such as announcements, are listed but can't be played.
+func (e *Episode) Playable() bool {
+	return !e.TextOnly && e.URL != ""
+}
+
+// matchesType reports whether the MIME type is what preferred asks for: a full
+// type such as "audio/mpeg", or just "audio" or "video"
+func matchesType(mimeType, preferred string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	preferred = strings.ToLower(strings.TrimSpace(preferred))
+	if strings.Contains(preferred, "/") {
+		return mimeType == preferred
+	}
+	major, _, _ := strings.Cut(mimeType, "/")
+	return major == preferred
+}
+
+// SelectEnclosure returns the alternative that best fits the preferred type and
+// quality, or nil if the episode has no alternatives. Alternatives of another
+// type are only chosen when none has the preferred one.
+func (e *Episode) SelectEnclosure(preferredType, quality string) *Enclosure {
+	var candidates []*Enclosure
+	for i := range e.Enclosures {
+		if preferredType == "" || matchesType(e.Enclosures[i].Type, preferredType) {
+			candidates = append(candidates, &e.Enclosures[i])
+		}
+	}
+	if len(candidates) == 0 {
+		for i := range e.Enclosures {
+			candidates = append(candidates, &e.Enclosures[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Feeds list their own choice first
+	best := candidates[0]
+	if quality != QualityHigh && quality != QualityLow {
+		return best
+	}
+	for _, candidate := range candidates[1:] {
+		better := compareQuality(*candidate, *best) > 0
+		if quality == QualityLow {
+			better = compareQuality(*candidate, *best) < 0
+		}
+		if better {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// compareQuality orders enclosures by bitrate, or by size when either bitrate
+// is unknown; 0 means they can't be told apart
+func compareQuality(a, b Enclosure) int {
+	x, y := int64(a.Bitrate), int64(b.Bitrate)
+	if x == 0 || y == 0 {
+		x, y = a.Length, b.Length
+	}
+	switch {
+	case x == 0 || y == 0:
+		return 0
+	case x > y:
+		return 1
+	case x < y:
+		return -1
+	}
+	return 0
+}
+
+// ApplyEnclosurePreference points each episode offered as several alternatives
+// at the one matching the podcast's preferred type and quality. Episode IDs are
+// derived from the feed's own enclosure, so they don't change.
+func (p *Podcast) ApplyEnclosurePreference() {
+	for _, episode := range p.Episodes {
+		if enclosure := episode.SelectEnclosure(p.PreferredType, p.PreferredQuality); enclosure != nil {
+			episode.URL = enclosure.URL
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import "testing"
+
+func TestSelectEnclosure(t *testing.T) {
+	episode := &Episode{
+		URL: "https://example.com/ep.mp3",
+		Enclosures: []Enclosure{
+			{URL: "https://example.com/ep.mp3", Type: "audio/mpeg", Bitrate: 128},
+			{URL: "https://example.com/ep.opus", Type: "audio/opus", Bitrate: 32},
+			{URL: "https://example.com/ep-hq.mp3", Type: "audio/mpeg", Bitrate: 256},
+			{URL: "https://example.com/ep.mp4", Type: "video/mp4", Length: 900000000},
+		},
+	}
+
+	tests := []struct {
+		preferredType string
+		quality       string
+		want          string
+	}{
+		{"", QualityDefault, "https://example.com/ep.mp3"},
+		{"", QualityHigh, "https://example.com/ep-hq.mp3"},
+		{"audio", QualityLow, "https://example.com/ep.opus"},
+		{"audio/mpeg", QualityLow, "https://example.com/ep.mp3"},
+		{"video", QualityDefault, "https://example.com/ep.mp4"},
+		{"text/html", QualityDefault, "https://example.com/ep.mp3"},
+	}
+	for _, tt := range tests {
+		got := episode.SelectEnclosure(tt.preferredType, tt.quality)
+		if got == nil || got.URL != tt.want {
+			t.Errorf("SelectEnclosure(%q, %q) = %+v, want %s", tt.preferredType, tt.quality, got, tt.want)
+		}
+	}
+
+	if (&Episode{URL: "https://example.com/only.mp3"}).SelectEnclosure("video", QualityHigh) != nil {
+		t.Error("Expected no choice for episodes without alternatives")
+	}
+}
+
+func TestApplyEnclosurePreference(t *testing.T) {
+	single := &Episode{URL: "https://example.com/single.mp3"}
+	multiple := &Episode{
+		URL: "https://example.com/ep.mp3",
+		Enclosures: []Enclosure{
+			{URL: "https://example.com/ep.mp3", Type: "audio/mpeg", Length: 40000000},
+			{URL: "https://example.com/ep.opus", Type: "audio/opus", Length: 10000000},
+		},
+	}
+	podcast := &Podcast{Episodes: []*Episode{single, multiple}, PreferredQuality: QualityLow}
+
+	podcast.ApplyEnclosurePreference()
+	if multiple.URL != "https://example.com/ep.opus" || single.URL != "https://example.com/single.mp3" {
+		t.Errorf("Unexpected URLs %q and %q", multiple.URL, single.URL)
+	}
+
+	podcast.PreferredQuality = QualityDefault
+	podcast.ApplyEnclosurePreference()
+	if multiple.URL != "https://example.com/ep.mp3" {
+		t.Errorf("Expected the feed's choice again, got %q", multiple.URL)
+	}
+}
+
+func TestEpisode_Playable(t *testing.T) {
+	if !(&Episode{URL: "https://example.com/ep.mp3"}).Playable() {
+		t.Error("Expected an episode with a URL to be playable")
+	}
+	if (&Episode{TextOnly: true}).Playable() || (&Episode{}).Playable() {
+		t.Error("Expected episodes without media not to be playable")
+	}
+}
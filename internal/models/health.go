@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// MaxRecentFeedErrors limits how many refresh errors are kept per podcast
+const MaxRecentFeedErrors = 10
+
+// FeedErrorThreshold is the number of consecutive failures after which a feed is
+// considered broken rather than temporarily unavailable
+const FeedErrorThreshold = 3
+
+// FeedError records a failed refresh of a podcast feed
+type FeedError struct {
+	Time       time.Time `json:"time"`
+	Message    string    `json:"message"`
+	HTTPStatus int       `json:"httpStatus,omitempty"`
+}
+
+// RecordRefreshSuccess records a successful fetch of the feed and schedules the next one
+func (p *Podcast) RecordRefreshSuccess(now time.Time, httpStatus int) {
+	p.LastSuccess = now
+	p.LastError = ""
+	p.Retired = false
+	p.RetiredAt = time.Time{}
+	p.LastHTTPStatus = httpStatus
+	p.ScheduleNextRefresh(now, true)
+}
+
+// RecordRefreshFailure records a failed fetch of the feed and schedules a retry.
+// httpStatus is 0 when the failure wasn't an HTTP error response.
+func (p *Podcast) RecordRefreshFailure(now time.Time, message string, httpStatus int) {
+	p.LastError = message
+	p.LastHTTPStatus = httpStatus
+
+	// Newest first
+	p.RecentErrors = append([]FeedError{{
+		Time:       now,
+		Message:    message,
+		HTTPStatus: httpStatus,
+	}}, p.RecentErrors...)
+	if len(p.RecentErrors) > MaxRecentFeedErrors {
+		p.RecentErrors = p.RecentErrors[:MaxRecentFeedErrors]
+	}
+
+	p.ScheduleNextRefresh(now, false)
+}
+
+// HasRefreshErrors reports whether the most recent refresh of the feed failed
+func (p *Podcast) HasRefreshErrors() bool {
+	return p.ConsecutiveFailures > 0
+}
+
+// IsBroken reports whether the feed has failed enough times in a row to need attention
+func (p *Podcast) IsBroken() bool {
+	return p.ConsecutiveFailures >= FeedErrorThreshold
+}
+
+// ParseIssues counts feed values that couldn't be understood during the last
+// refresh, with an example of each to help diagnose the feed
+type ParseIssues struct {
+	Dates          int    `json:"dates,omitempty"`
+	DateSample     string `json:"dateSample,omitempty"`
+	Durations      int    `json:"durations,omitempty"`
+	DurationSample string `json:"durationSample,omitempty"`
+}
+
+// AddDate records a publish date that couldn't be parsed
+func (pi *ParseIssues) AddDate(value string) {
+	pi.Dates++
+	if pi.DateSample == "" {
+		pi.DateSample = value
+	}
+}
+
+// AddDuration records a duration that couldn't be parsed
+func (pi *ParseIssues) AddDuration(value string) {
+	pi.Durations++
+	if pi.DurationSample == "" {
+		pi.DurationSample = value
+	}
+}
+
+// Total returns the number of values that couldn't be parsed
+func (pi *ParseIssues) Total() int {
+	if pi == nil {
+		return 0
+	}
+	return pi.Dates + pi.Durations
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRecordRefreshFailure(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	podcast := &Podcast{}
+
+	podcast.RecordRefreshFailure(now, "server returned status 404", 404)
+
+	if !podcast.HasRefreshErrors() {
+		t.Error("Expected podcast to have refresh errors")
+	}
+	if podcast.IsBroken() {
+		t.Error("Expected a single failure not to mark the feed broken")
+	}
+	if podcast.LastError != "server returned status 404" {
+		t.Errorf("Unexpected last error: %q", podcast.LastError)
+	}
+	if podcast.LastHTTPStatus != 404 {
+		t.Errorf("Expected HTTP status 404, got %d", podcast.LastHTTPStatus)
+	}
+	if len(podcast.RecentErrors) != 1 || podcast.RecentErrors[0].HTTPStatus != 404 {
+		t.Errorf("Expected one recent error with status 404, got %+v", podcast.RecentErrors)
+	}
+
+	for i := 1; i < FeedErrorThreshold; i++ {
+		podcast.RecordRefreshFailure(now.Add(time.Duration(i)*time.Hour), "timeout", 0)
+	}
+	if !podcast.IsBroken() {
+		t.Errorf("Expected feed to be broken after %d failures", FeedErrorThreshold)
+	}
+	if podcast.RecentErrors[0].Message != "timeout" {
+		t.Errorf("Expected newest error first, got %q", podcast.RecentErrors[0].Message)
+	}
+}
+
+func TestRecordRefreshFailure_CapsRecentErrors(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	podcast := &Podcast{}
+
+	for i := 0; i < MaxRecentFeedErrors+5; i++ {
+		podcast.RecordRefreshFailure(now, fmt.Sprintf("error %d", i), 500)
+	}
+
+	if len(podcast.RecentErrors) != MaxRecentFeedErrors {
+		t.Errorf("Expected %d recent errors, got %d", MaxRecentFeedErrors, len(podcast.RecentErrors))
+	}
+	if podcast.RecentErrors[0].Message != fmt.Sprintf("error %d", MaxRecentFeedErrors+4) {
+		t.Errorf("Expected newest error first, got %q", podcast.RecentErrors[0].Message)
+	}
+}
+
+func TestRecordRefreshSuccess(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	podcast := &Podcast{}
+
+	podcast.RecordRefreshFailure(now, "timeout", 0)
+	podcast.RecordRefreshSuccess(now.Add(time.Hour), 200)
+
+	if podcast.HasRefreshErrors() {
+		t.Error("Expected errors to clear after a successful refresh")
+	}
+	if podcast.LastError != "" {
+		t.Errorf("Expected last error to be cleared, got %q", podcast.LastError)
+	}
+	if !podcast.LastSuccess.Equal(now.Add(time.Hour)) {
+		t.Errorf("Unexpected last success time: %v", podcast.LastSuccess)
+	}
+	if len(podcast.RecentErrors) != 1 {
+		t.Errorf("Expected error history to be kept, got %d entries", len(podcast.RecentErrors))
+	}
+}
+
+func TestParseIssues(t *testing.T) {
+	var none *ParseIssues
+	if none.Total() != 0 {
+		t.Error("Expected nil parse issues to total 0")
+	}
+
+	issues := &ParseIssues{}
+	issues.AddDate("last tuesday")
+	issues.AddDate("soon")
+	issues.AddDuration("a while")
+
+	if issues.Total() != 3 {
+		t.Errorf("Expected 3 issues, got %d", issues.Total())
+	}
+	if issues.DateSample != "last tuesday" || issues.DurationSample != "a while" {
+		t.Errorf("Expected the first value of each kind to be kept, got %+v", issues)
+	}
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EpisodeIDURL returns the URL episode IDs are derived from. It stays the
+// original feed URL after the feed moves so existing episodes keep their IDs.
+func (p *Podcast) EpisodeIDURL() string {
+	if p.IdentityURL != "" {
+		return p.IdentityURL
+	}
+	return p.URL
+}
+
+// MoveTo updates the feed URL after a permanent move, remembering the old one.
+// It returns false if the podcast is already at newURL.
+func (p *Podcast) MoveTo(newURL string) bool {
+	if newURL == "" || newURL == p.URL {
+		return false
+	}
+
+	if p.IdentityURL == "" {
+		p.IdentityURL = p.URL
+	}
+	p.PreviousURLs = append(p.PreviousURLs, p.URL)
+	p.URL = newURL
+	return true
+}
+
+// HasURL reports whether url is, or is equivalent to, the podcast's current or a
+// previous feed URL, see SameFeedURL
+func (p *Podcast) HasURL(url string) bool {
+	if SameFeedURL(p.URL, url) {
+		return true
+	}
+	for _, previous := range p.PreviousURLs {
+		if SameFeedURL(previous, url) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddAlias records another URL the feed is reachable at, such as that of a
+// duplicate merged into it, so subscribing to it again is recognised
+func (p *Podcast) AddAlias(url string) {
+	if url != "" && !p.HasURL(url) {
+		p.PreviousURLs = append(p.PreviousURLs, url)
+	}
+}
+
+// trackingParams are query parameters added for analytics that don't change
+// which feed a URL points to
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"mc_cid": true, "mc_eid": true, "igshid": true, "_hsenc": true, "_hsmi": true,
+}
+
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "utm_") || trackingParams[name]
+}
+
+// NormalizeFeedURL returns the canonical form of a web feed URL: lowercase scheme
+// and host, no default port, fragment or tracking parameters. Other URLs are
+// returned unchanged.
+func NormalizeFeedURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return rawURL
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	// Filter the raw query so the remaining parameters keep their encoding and order
+	if u.RawQuery != "" {
+		var kept []string
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			name, _, _ := strings.Cut(pair, "=")
+			if decoded, err := url.QueryUnescape(name); err == nil {
+				name = decoded
+			}
+			if pair != "" && !isTrackingParam(name) {
+				kept = append(kept, pair)
+			}
+		}
+		u.RawQuery = strings.Join(kept, "&")
+	}
+	return u.String()
+}
+
+// feedURLKey reduces a feed URL to what identifies the feed, ignoring the
+// differences NormalizeFeedURL removes as well as http versus https, trailing
+// slashes and the order of query parameters
+func feedURLKey(rawURL string) string {
+	normalized := NormalizeFeedURL(rawURL)
+	u, err := url.Parse(normalized)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return normalized
+	}
+
+	key := u.Host + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		pairs := strings.Split(u.RawQuery, "&")
+		sort.Strings(pairs)
+		key += "?" + strings.Join(pairs, "&")
+	}
+	return key
+}
+
+// SameFeedURL reports whether two URLs point to the same feed once normalized
+func SameFeedURL(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return a == b || feedURLKey(a) == feedURLKey(b)
+}
+
+// Retire marks the feed as permanently gone so it is no longer refreshed
+func (p *Podcast) Retire(now time.Time) {
+	if !p.Retired {
+		p.Retired = true
+		p.RetiredAt = now
+	}
+}
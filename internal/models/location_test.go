@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoveTo_PreservesEpisodeIdentity(t *testing.T) {
+	podcast := &Podcast{URL: "https://old.example.com/feed.xml"}
+
+	if podcast.EpisodeIDURL() != "https://old.example.com/feed.xml" {
+		t.Errorf("Expected ID URL to default to feed URL, got %s", podcast.EpisodeIDURL())
+	}
+
+	if !podcast.MoveTo("https://new.example.com/feed.xml") {
+		t.Fatal("Expected move to a new URL to succeed")
+	}
+	if podcast.URL != "https://new.example.com/feed.xml" {
+		t.Errorf("Expected URL to be updated, got %s", podcast.URL)
+	}
+	if podcast.EpisodeIDURL() != "https://old.example.com/feed.xml" {
+		t.Errorf("Expected ID URL to stay the original URL, got %s", podcast.EpisodeIDURL())
+	}
+
+	podcast.MoveTo("https://newer.example.com/feed.xml")
+	if podcast.EpisodeIDURL() != "https://old.example.com/feed.xml" {
+		t.Errorf("Expected ID URL to survive a second move, got %s", podcast.EpisodeIDURL())
+	}
+	if !podcast.HasURL("https://old.example.com/feed.xml") || !podcast.HasURL("https://new.example.com/feed.xml") {
+		t.Error("Expected previous URLs to be remembered")
+	}
+
+	if podcast.MoveTo("https://newer.example.com/feed.xml") {
+		t.Error("Expected move to the current URL to be a no-op")
+	}
+}
+
+func TestRetire(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	podcast := &Podcast{}
+
+	podcast.Retire(now)
+	if !podcast.Retired || !podcast.RetiredAt.Equal(now) {
+		t.Errorf("Expected podcast retired at %v, got %v/%v", now, podcast.Retired, podcast.RetiredAt)
+	}
+	if podcast.IsRefreshDue(now.Add(365 * 24 * time.Hour)) {
+		t.Error("Expected retired podcast never to be due")
+	}
+
+	podcast.RecordRefreshSuccess(now.Add(time.Hour), 200)
+	if podcast.Retired {
+		t.Error("Expected a successful refresh to un-retire the podcast")
+	}
+}
+
+func TestSubscriptions_FindByURL(t *testing.T) {
+	podcast := &Podcast{URL: "https://old.example.com/feed.xml"}
+	podcast.MoveTo("https://new.example.com/feed.xml")
+	subs := &Subscriptions{Podcasts: []*Podcast{podcast}}
+
+	if subs.FindByURL("https://old.example.com/feed.xml") != podcast {
+		t.Error("Expected to find podcast by previous URL")
+	}
+	if subs.FindByURL("https://new.example.com/feed.xml") != podcast {
+		t.Error("Expected to find podcast by current URL")
+	}
+	if subs.FindByURL("https://other.example.com/feed.xml") != nil {
+		t.Error("Expected no match for unknown URL")
+	}
+}
+
+func TestNormalizeFeedURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"HTTPS://Feeds.Example.COM:443/show.xml#latest", "https://feeds.example.com/show.xml"},
+		{"http://example.com:80/feed?utm_source=twitter&id=7&fbclid=abc", "http://example.com/feed?id=7"},
+		{"https://example.com/feed?UTM_Medium=x", "https://example.com/feed"},
+		{"https://example.com:8443/feed/", "https://example.com:8443/feed/"},
+		{"https://example.com/feed?token=a%2Bb", "https://example.com/feed?token=a%2Bb"},
+		{"file:///srv/My%20Lectures", "file:///srv/My%20Lectures"},
+		{" not a url ", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeFeedURL(tt.input); got != tt.want {
+			t.Errorf("NormalizeFeedURL(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSameFeedURL(t *testing.T) {
+	same := [][2]string{
+		{"http://example.com/feed", "https://example.com/feed"},
+		{"https://example.com/feed/", "https://EXAMPLE.com/feed"},
+		{"https://example.com/feed?a=1&b=2", "https://example.com/feed?b=2&a=1&utm_campaign=x"},
+	}
+	for _, pair := range same {
+		if !SameFeedURL(pair[0], pair[1]) {
+			t.Errorf("Expected %s and %s to be the same feed", pair[0], pair[1])
+		}
+	}
+
+	different := [][2]string{
+		{"https://example.com/feed", "https://example.com/feed2"},
+		{"https://example.com/feed?id=1", "https://example.com/feed?id=2"},
+		{"https://a.example.com/feed", "https://b.example.com/feed"},
+		{"", ""},
+	}
+	for _, pair := range different {
+		if SameFeedURL(pair[0], pair[1]) {
+			t.Errorf("Expected %s and %s to be different feeds", pair[0], pair[1])
+		}
+	}
+
+	podcast := &Podcast{URL: "https://example.com/feed"}
+	podcast.AddAlias("https://mirror.example.net/feed")
+	podcast.AddAlias("http://example.com/feed/")
+	if !podcast.HasURL("https://mirror.example.net/feed") || len(podcast.PreviousURLs) != 1 {
+		t.Errorf("Expected only the new alias to be recorded, got %v", podcast.PreviousURLs)
+	}
+}
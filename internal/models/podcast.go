@@ -18,10 +18,55 @@ type Podcast struct {
 	
 	// Converted description (persisted for performance)
 	ConvertedDescription string `json:"convertedDescription,omitempty"`
+
+	// Refresh scheduling, see RefreshInterval
+	NextRefresh         time.Time `json:"nextRefresh,omitempty"`
+	ConsecutiveFailures int       `json:"consecutiveFailures,omitempty"`
+
+	// Feed health, see RecordRefreshSuccess and RecordRefreshFailure
+	LastSuccess    time.Time    `json:"lastSuccess,omitempty"`
+	LastError      string       `json:"lastError,omitempty"`
+	LastHTTPStatus int          `json:"lastHTTPStatus,omitempty"`
+	RecentErrors   []FeedError  `json:"recentErrors,omitempty"`
+	ParseIssues    *ParseIssues `json:"parseIssues,omitempty"`
+
+	// Feed location history, see MoveTo and Retire
+	IdentityURL  string    `json:"identityURL,omitempty"`
+	PreviousURLs []string  `json:"previousURLs,omitempty"`
+	Retired      bool      `json:"retired,omitempty"`
+	RetiredAt    time.Time `json:"retiredAt,omitempty"`
+
+	// SelfURL is the feed's own address as declared by its <atom:link rel="self">,
+	// used to recognise the same feed reached through another URL
+	SelfURL string `json:"selfURL,omitempty"`
+
+	// Paginated is set when the feed links to older pages, so refreshes of the
+	// first page keep episodes fetched from the rest of the history
+	Paginated bool `json:"paginated,omitempty"`
+
+	// HTTP validators of the last fetch, sent with refreshes so unchanged feeds
+	// aren't downloaded again
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+
+	// Which of an episode's alternative enclosures to play and download: a MIME
+	// type or just "audio" or "video", and a quality, see QualityHigh
+	PreferredType    string `json:"preferredType,omitempty"`
+	PreferredQuality string `json:"preferredQuality,omitempty"`
+
+	// AudioFilters turns audio filters on or off for this podcast, overriding
+	// the global setting; filters missing from it follow the setting
+	AudioFilters map[string]bool `json:"audioFilters,omitempty"`
+
+	// SkipIntro is how much of the start of unplayed episodes is skipped, and
+	// SkipOutro how much of their end, which then counts as the end of the episode
+	SkipIntro time.Duration `json:"skipIntro,omitempty"`
+	SkipOutro time.Duration `json:"skipOutro,omitempty"`
 }
 
 type Episode struct {
 	ID           string        `json:"id"`
+	GUID         string        `json:"guid,omitempty"` // The feed's own episode identifier, if any
 	Title        string        `json:"title"`
 	Description  string        `json:"description"`
 	URL          string        `json:"url"`
@@ -34,6 +79,16 @@ type Episode struct {
 	DownloadSize int64         `json:"downloadSize,omitempty"`
 	DownloadDate time.Time     `json:"downloadDate,omitempty"`
 	LastPlayed   time.Time     `json:"lastPlayed,omitempty"`
+
+	// Enclosures lists the alternatives of episodes offered in several formats or
+	// qualities; URL is the one in use. TextOnly marks items without any media.
+	Enclosures []Enclosure `json:"enclosures,omitempty"`
+	TextOnly   bool        `json:"textOnly,omitempty"`
+
+	// FileSize and Artist describe the files of local folders; the size tells
+	// whether a file changed since the folder was last scanned
+	FileSize int64  `json:"fileSize,omitempty"`
+	Artist   string `json:"artist,omitempty"`
 	
 	// Converted description (persisted for performance)
 	ConvertedDescription string `json:"convertedDescription,omitempty"`
@@ -46,7 +101,15 @@ func GenerateEpisodeID(podcastURL, episodeURL string, publishDate time.Time) str
 	return fmt.Sprintf("%x", h.Sum(nil))[:16] // First 16 chars for filename safety
 }
 
-// GenerateID generates an ID for this episode using the parent podcast URL
+// GenerateID generates an ID for this episode using the parent podcast URL.
+// Text-only episodes have no URL, so their GUID or title is used instead.
 func (e *Episode) GenerateID(podcastURL string) {
-	e.ID = GenerateEpisodeID(podcastURL, e.URL, e.PublishDate)
+	key := e.URL
+	if key == "" {
+		key = e.GUID
+		if key == "" {
+			key = e.Title
+		}
+	}
+	e.ID = GenerateEpisodeID(podcastURL, key, e.PublishDate)
 }
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// MinRefreshInterval is the shortest interval between fetches of a feed
+	MinRefreshInterval = 30 * time.Minute
+
+	// MaxRefreshInterval is the longest interval between fetches of a healthy feed
+	MaxRefreshInterval = 3 * 24 * time.Hour
+
+	// MaxFailureBackoff is the longest interval between fetches of a failing feed
+	MaxFailureBackoff = 7 * 24 * time.Hour
+
+	// DefaultRefreshInterval is used when there isn't enough history to estimate a cadence
+	DefaultRefreshInterval = 6 * time.Hour
+
+	// cadenceSampleSize is how many recent gaps between episodes are considered
+	cadenceSampleSize = 10
+
+	// checksPerCadence is how many times a feed is checked per expected publishing period
+	checksPerCadence = 3
+)
+
+// ExpectedCadence estimates how often the podcast publishes, using the median gap
+// between its most recent episodes. It returns 0 when there isn't enough history.
+func (p *Podcast) ExpectedCadence() time.Duration {
+	var dates []time.Time
+	for _, episode := range p.Episodes {
+		if !episode.PublishDate.IsZero() {
+			dates = append(dates, episode.PublishDate)
+		}
+	}
+	if len(dates) < 2 {
+		return 0
+	}
+
+	// Newest first
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].After(dates[j])
+	})
+
+	var gaps []time.Duration
+	for i := 1; i < len(dates) && len(gaps) < cadenceSampleSize; i++ {
+		if gap := dates[i-1].Sub(dates[i]); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return 0
+	}
+
+	sort.Slice(gaps, func(i, j int) bool {
+		return gaps[i] < gaps[j]
+	})
+	return gaps[len(gaps)/2]
+}
+
+// RefreshInterval returns how long to wait before fetching the feed again, based on
+// its publishing cadence and backing off exponentially after consecutive failures
+func (p *Podcast) RefreshInterval() time.Duration {
+	interval := DefaultRefreshInterval
+	if cadence := p.ExpectedCadence(); cadence > 0 {
+		interval = cadence / checksPerCadence
+	}
+
+	if interval < MinRefreshInterval {
+		interval = MinRefreshInterval
+	} else if interval > MaxRefreshInterval {
+		interval = MaxRefreshInterval
+	}
+
+	for i := 0; i < p.ConsecutiveFailures && interval < MaxFailureBackoff; i++ {
+		interval *= 2
+	}
+	if interval > MaxFailureBackoff {
+		interval = MaxFailureBackoff
+	}
+
+	return interval
+}
+
+// ScheduleNextRefresh records the outcome of a fetch and sets when the feed is next due
+func (p *Podcast) ScheduleNextRefresh(now time.Time, success bool) {
+	if success {
+		p.ConsecutiveFailures = 0
+	} else {
+		p.ConsecutiveFailures++
+	}
+	p.NextRefresh = now.Add(p.RefreshInterval())
+}
+
+// IsRefreshDue reports whether the feed should be fetched by a "refresh due" operation.
+// Retired feeds are never due.
+func (p *Podcast) IsRefreshDue(now time.Time) bool {
+	if p.Retired {
+		return false
+	}
+	return p.NextRefresh.IsZero() || !now.Before(p.NextRefresh)
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// podcastWithGap creates a podcast with count episodes published gap apart
+func podcastWithGap(count int, gap time.Duration) *Podcast {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	podcast := &Podcast{}
+	for i := 0; i < count; i++ {
+		podcast.Episodes = append(podcast.Episodes, &Episode{
+			PublishDate: start.Add(time.Duration(i) * gap),
+		})
+	}
+	return podcast
+}
+
+func TestExpectedCadence(t *testing.T) {
+	tests := []struct {
+		name     string
+		podcast  *Podcast
+		expected time.Duration
+	}{
+		{"no episodes", &Podcast{}, 0},
+		{"single episode", podcastWithGap(1, 24*time.Hour), 0},
+		{"daily", podcastWithGap(5, 24*time.Hour), 24 * time.Hour},
+		{"weekly", podcastWithGap(8, 7*24*time.Hour), 7 * 24 * time.Hour},
+		{"undated episodes", &Podcast{Episodes: []*Episode{{}, {}, {}}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.podcast.ExpectedCadence(); got != tt.expected {
+				t.Errorf("ExpectedCadence() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpectedCadence_IgnoresOutliers(t *testing.T) {
+	podcast := podcastWithGap(6, 24*time.Hour)
+	// A long hiatus before the first episode shouldn't change the median
+	podcast.Episodes = append(podcast.Episodes, &Episode{
+		PublishDate: podcast.Episodes[0].PublishDate.Add(-90 * 24 * time.Hour),
+	})
+
+	if got := podcast.ExpectedCadence(); got != 24*time.Hour {
+		t.Errorf("ExpectedCadence() = %v, want %v", got, 24*time.Hour)
+	}
+}
+
+func TestRefreshInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		podcast  *Podcast
+		expected time.Duration
+	}{
+		{"no history uses default", &Podcast{}, DefaultRefreshInterval},
+		{"hourly clamps to minimum", podcastWithGap(10, time.Hour), MinRefreshInterval},
+		{"daily checks several times a day", podcastWithGap(10, 24*time.Hour), 8 * time.Hour},
+		{"quarterly clamps to maximum", podcastWithGap(4, 90*24*time.Hour), MaxRefreshInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.podcast.RefreshInterval(); got != tt.expected {
+				t.Errorf("RefreshInterval() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRefreshInterval_FailureBackoff(t *testing.T) {
+	podcast := &Podcast{}
+
+	podcast.ConsecutiveFailures = 1
+	if got := podcast.RefreshInterval(); got != 2*DefaultRefreshInterval {
+		t.Errorf("Expected interval to double after one failure, got %v", got)
+	}
+
+	podcast.ConsecutiveFailures = 20
+	if got := podcast.RefreshInterval(); got != MaxFailureBackoff {
+		t.Errorf("Expected interval to be capped at %v, got %v", MaxFailureBackoff, got)
+	}
+}
+
+func TestScheduleNextRefresh(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	podcast := &Podcast{}
+
+	if !podcast.IsRefreshDue(now) {
+		t.Error("Expected a never-refreshed podcast to be due")
+	}
+
+	podcast.ScheduleNextRefresh(now, false)
+	if podcast.ConsecutiveFailures != 1 {
+		t.Errorf("Expected 1 consecutive failure, got %d", podcast.ConsecutiveFailures)
+	}
+	if podcast.IsRefreshDue(now) {
+		t.Error("Expected podcast not to be due right after a refresh")
+	}
+	if !podcast.IsRefreshDue(now.Add(2 * DefaultRefreshInterval)) {
+		t.Error("Expected podcast to be due once the backoff interval has passed")
+	}
+
+	podcast.ScheduleNextRefresh(now, true)
+	if podcast.ConsecutiveFailures != 0 {
+		t.Errorf("Expected failures to reset after success, got %d", podcast.ConsecutiveFailures)
+	}
+	if !podcast.NextRefresh.Equal(now.Add(DefaultRefreshInterval)) {
+		t.Errorf("Expected next refresh at %v, got %v", now.Add(DefaultRefreshInterval), podcast.NextRefresh)
+	}
+}
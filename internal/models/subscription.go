@@ -124,7 +124,7 @@ func (s *Subscriptions) ConvertMissingDescriptions() bool {
 
 func (s *Subscriptions) Add(podcast *Podcast) {
 	for _, p := range s.Podcasts {
-		if p.URL == podcast.URL {
+		if SameFeedURL(p.URL, podcast.URL) {
 			return
 		}
 	}
@@ -145,6 +145,16 @@ func (s *Subscriptions) Add(podcast *Podcast) {
 	}
 }
 
+// FindByURL returns the podcast whose current or previous feed URL is url
+func (s *Subscriptions) FindByURL(url string) *Podcast {
+	for _, p := range s.Podcasts {
+		if p.HasURL(url) {
+			return p
+		}
+	}
+	return nil
+}
+
 func (s *Subscriptions) Remove(url string) {
 	for i, p := range s.Podcasts {
 		if p.URL == url {
@@ -0,0 +1,143 @@
+package player
+
+import (
+	"log"
+	"time"
+)
+
+type PlayerState int
+
+const (
+	StateStopped PlayerState = iota
+	StatePlaying
+	StatePaused
+)
+
+type Progress struct {
+	Position time.Duration
+	Duration time.Duration
+}
+
+// Chapter is a chapter marked in the file being played
+type Chapter struct {
+	Title string
+	Start time.Duration
+}
+
+// EventType identifies what happened in an Event
+type EventType int
+
+const (
+	// EventEndOfFile is sent when the current file plays to its end, not when
+	// it is stopped or replaced by another
+	EventEndOfFile EventType = iota
+
+	// EventError is sent when the current file stops because it could not be
+	// played, such as on a network error or an unsupported format
+	EventError
+
+	// EventRecovered is sent when mpv crashed and was restarted, resuming the
+	// current file where it was
+	EventRecovered
+
+	// EventNextFile is sent when the current file played to its end and the
+	// one given to Preload took over without a gap. It is sent instead of
+	// EventEndOfFile.
+	EventNextFile
+)
+
+// Event is something the backend reports on its own, outside of any call
+type Event struct {
+	Type EventType
+	Err  error  // why playback failed or mpv was restarted
+	URL  string // the file now playing, for EventNextFile
+}
+
+// eventBuffer is how many events a backend holds for a slow reader
+const eventBuffer = 16
+
+// sendEvent delivers event without blocking the backend if nobody is reading
+func sendEvent(events chan Event, event Event) {
+	select {
+	case events <- event:
+	default:
+		log.Printf("Player: Dropped event %v, channel full", event.Type)
+	}
+}
+
+// Backend plays audio for the app. MPV is the real implementation; Fake plays
+// nothing and is driven by tests.
+type Backend interface {
+	// StartIdle prepares the backend so the first Play starts quickly
+	StartIdle() error
+
+	// Play starts playing url from the beginning
+	Play(url string) error
+
+	// SwitchTrack replaces whatever is playing with url, starting at start.
+	// GetPosition reports start while the file is opened.
+	SwitchTrack(url string, start time.Duration) error
+
+	// Preload queues url to play from start as soon as the current file ends,
+	// so there is no gap between them, with outro as in SetOutro. It replaces
+	// any file preloaded before; an empty url cancels it. Play and Stop cancel
+	// it too.
+	Preload(url string, start, outro time.Duration) error
+
+	// SetOutro makes the current file end outro before its real end: reaching
+	// that point counts as the end of the file, as if it had played out. Play
+	// resets it.
+	SetOutro(outro time.Duration) error
+
+	// Pause and Resume control playback; Resume first goes back by the smart
+	// rewind for the time spent paused, see SetSmartRewind
+	Pause() error
+	Resume() error
+	TogglePause() error
+	SetSmartRewind(rewind SmartRewind)
+
+	// Stop ends playback and releases the backend's resources; StopKeepIdle
+	// ends playback but keeps the backend ready for the next Play
+	Stop() error
+	StopKeepIdle() error
+
+	// Cleanup releases everything when the app shuts down
+	Cleanup()
+
+	// Seek moves relative to the current position, or to an absolute position
+	// for values over 5 minutes; SeekAbsolute always seeks to seconds
+	Seek(seconds int) error
+	SeekAbsolute(seconds int) error
+
+	GetVolume() (int, error)
+	SetVolume(volume int) error
+	GetSpeed() (float64, error)
+	SetSpeed(speed float64) error
+	IsMuted() bool
+	ToggleMute() error
+
+	// SetAudioFilters replaces the audio processing, see AudioFilters
+	SetAudioFilters(filters AudioFilters) error
+	GetAudioFilters() AudioFilters
+
+	GetPosition() (time.Duration, error)
+	GetDuration() (time.Duration, error)
+	IsPlaying() bool
+	IsPaused() bool
+	GetState() PlayerState
+
+	// Chapters returns the chapters of the current file in order, if it has any
+	Chapters() []Chapter
+
+	// Progress delivers the position about once a second while playing
+	Progress() <-chan Progress
+
+	// Events delivers events such as the end of the current file or a failure
+	// to play it
+	Events() <-chan Event
+}
+
+var (
+	_ Backend = (*MPV)(nil)
+	_ Backend = (*Fake)(nil)
+)
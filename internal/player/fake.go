@@ -0,0 +1,394 @@
+package player
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fake is a Backend that plays nothing. Time only passes when Advance is called,
+// so tests of queue advancing, completion and position saving are deterministic.
+type Fake struct {
+	mu         sync.Mutex
+	state      PlayerState
+	url        string
+	position   time.Duration
+	duration   time.Duration
+	volume     int
+	speed      float64
+	isMuted    bool
+	filters    AudioFilters
+	next       string
+	nextStart  time.Duration
+	nextOutro  time.Duration
+	outro      time.Duration
+	rewind     SmartRewind
+	pausedFor  time.Duration
+	loaded     []string
+	durations  map[string]time.Duration
+	chapters   map[string][]Chapter
+	failures   map[string]error
+	progressCh chan Progress
+	events     chan Event
+}
+
+// NewFake returns a stopped fake backend
+func NewFake() *Fake {
+	return &Fake{
+		volume:     100,
+		speed:      1.0,
+		durations:  make(map[string]time.Duration),
+		chapters:   make(map[string][]Chapter),
+		failures:   make(map[string]error),
+		progressCh: make(chan Progress, 1),
+		events:     make(chan Event, eventBuffer),
+	}
+}
+
+// SetDuration sets the duration reported once url is loaded; unknown URLs have none
+func (f *Fake) SetDuration(url string, duration time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.durations[url] = duration
+}
+
+// SetChapters sets the chapters reported once url is loaded
+func (f *Fake) SetChapters(url string, chapters []Chapter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.chapters[url] = chapters
+}
+
+// FailLoading makes loading url fail with err
+func (f *Fake) FailLoading(url string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[url] = err
+}
+
+// URL returns the file being played, or "" when stopped
+func (f *Fake) URL() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.url
+}
+
+// Loaded returns every URL loaded so far, in order
+func (f *Fake) Loaded() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.loaded...)
+}
+
+// Preloaded returns the file given to Preload and where it starts
+func (f *Fake) Preloaded() (string, time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.next, f.nextStart
+}
+
+// Advance plays for d, reporting the new position. Reaching the duration, or
+// the outro before it, ends the file as mpv would: the preloaded file starts
+// and EventNextFile is sent, or without one playback stops and EventEndOfFile
+// is sent. While paused, d only adds to the length of the pause.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	if f.state == StatePaused {
+		f.pausedFor += d
+	}
+	if f.state != StatePlaying {
+		f.mu.Unlock()
+		return
+	}
+	f.position += d
+	ended := f.duration > 0 && f.position >= f.end()
+	if ended {
+		f.position = f.duration
+		f.state = StateStopped
+	}
+	progress := Progress{Position: f.position, Duration: f.duration}
+	event := Event{Type: EventEndOfFile}
+	if ended && f.next != "" {
+		f.url = f.next
+		f.position = f.nextStart
+		f.duration = f.durations[f.next]
+		f.outro = f.nextOutro
+		f.state = StatePlaying
+		f.loaded = append(f.loaded, f.next)
+		f.next = ""
+		event = Event{Type: EventNextFile, URL: f.url}
+	}
+	f.mu.Unlock()
+
+	select {
+	case f.progressCh <- progress:
+	default:
+	}
+	if ended {
+		sendEvent(f.events, event)
+	}
+}
+
+// Finish plays the current file to its end
+func (f *Fake) Finish() {
+	f.mu.Lock()
+	remaining := f.end() - f.position
+	f.mu.Unlock()
+	f.Advance(remaining)
+}
+
+// end returns where the current file ends: at its outro if it has one
+func (f *Fake) end() time.Duration {
+	if f.outro > 0 && f.duration > f.outro {
+		return f.duration - f.outro
+	}
+	return f.duration
+}
+
+// Fail stops the current file as mpv does when it cannot be played: the
+// position is kept and EventError is sent with err
+func (f *Fake) Fail(err error) {
+	f.mu.Lock()
+	if f.state == StateStopped {
+		f.mu.Unlock()
+		return
+	}
+	f.state = StateStopped
+	f.mu.Unlock()
+
+	sendEvent(f.events, Event{Type: EventError, Err: err})
+}
+
+func (f *Fake) StartIdle() error {
+	return nil
+}
+
+func (f *Fake) Play(url string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.failures[url]; err != nil {
+		return fmt.Errorf("failed to load file: %w", err)
+	}
+	f.url = url
+	f.position = 0
+	f.duration = f.durations[url]
+	f.state = StatePlaying
+	f.next = ""
+	f.outro = 0
+	f.loaded = append(f.loaded, url)
+	return nil
+}
+
+func (f *Fake) SwitchTrack(url string, start time.Duration) error {
+	if err := f.Play(url); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.position = start
+	return nil
+}
+
+func (f *Fake) Preload(url string, start, outro time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.state == StateStopped {
+		return nil
+	}
+	f.next = url
+	f.nextStart = start
+	f.nextOutro = outro
+	return nil
+}
+
+func (f *Fake) SetOutro(outro time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.outro = max(outro, 0)
+	return nil
+}
+
+func (f *Fake) Pause() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.state == StatePlaying {
+		f.state = StatePaused
+		f.pausedFor = 0
+	}
+	return nil
+}
+
+func (f *Fake) Resume() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.state == StatePaused {
+		f.position = max(f.position-f.rewind.Amount(f.pausedFor), 0)
+		f.state = StatePlaying
+	}
+	return nil
+}
+
+func (f *Fake) SetSmartRewind(rewind SmartRewind) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rewind = rewind
+}
+
+func (f *Fake) TogglePause() error {
+	if f.GetState() == StatePaused {
+		return f.Resume()
+	}
+	return f.Pause()
+}
+
+func (f *Fake) Stop() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = StateStopped
+	f.url = ""
+	f.position = 0
+	f.duration = 0
+	f.next = ""
+	f.outro = 0
+	return nil
+}
+
+func (f *Fake) StopKeepIdle() error {
+	return f.Stop()
+}
+
+func (f *Fake) Cleanup() {}
+
+func (f *Fake) Seek(seconds int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.state == StateStopped {
+		return nil
+	}
+	// Like mpv's backend, large values are absolute positions for resuming
+	target := f.position + time.Duration(seconds)*time.Second
+	if seconds > 300 {
+		target = time.Duration(seconds) * time.Second
+	}
+	f.seekTo(target)
+	return nil
+}
+
+func (f *Fake) SeekAbsolute(seconds int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.state == StateStopped {
+		return nil
+	}
+	f.seekTo(time.Duration(seconds) * time.Second)
+	return nil
+}
+
+// seekTo moves to target, kept between the start and a second before the end
+func (f *Fake) seekTo(target time.Duration) {
+	if f.duration > 0 && target > f.duration-time.Second {
+		target = f.duration - time.Second
+	}
+	if target < 0 {
+		target = 0
+	}
+	f.position = target
+}
+
+func (f *Fake) GetVolume() (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.volume, nil
+}
+
+func (f *Fake) SetVolume(volume int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.volume = min(max(volume, 0), 100)
+	return nil
+}
+
+func (f *Fake) GetSpeed() (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.speed, nil
+}
+
+func (f *Fake) SetSpeed(speed float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.speed = min(max(speed, 0.25), 4.0)
+	return nil
+}
+
+func (f *Fake) IsMuted() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.isMuted
+}
+
+func (f *Fake) ToggleMute() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.isMuted = !f.isMuted
+	return nil
+}
+
+func (f *Fake) SetAudioFilters(filters AudioFilters) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.filters = filters
+	return nil
+}
+
+func (f *Fake) GetAudioFilters() AudioFilters {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.filters
+}
+
+func (f *Fake) GetPosition() (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.position, nil
+}
+
+func (f *Fake) GetDuration() (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.duration, nil
+}
+
+func (f *Fake) IsPlaying() bool {
+	return f.GetState() == StatePlaying
+}
+
+func (f *Fake) IsPaused() bool {
+	return f.GetState() == StatePaused
+}
+
+func (f *Fake) GetState() PlayerState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state
+}
+
+func (f *Fake) Chapters() []Chapter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.state == StateStopped {
+		return nil
+	}
+	return f.chapters[f.url]
+}
+
+func (f *Fake) Progress() <-chan Progress {
+	return f.progressCh
+}
+
+func (f *Fake) Events() <-chan Event {
+	return f.events
+}
@@ -0,0 +1,88 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the audio filters, as used in settings and commands
+const (
+	FilterSkipSilence = "silence"
+	FilterNormalize   = "normalize"
+	FilterVoiceBoost  = "voice"
+)
+
+// FilterNames lists the audio filters in the order they are applied
+var FilterNames = []string{FilterSkipSilence, FilterVoiceBoost, FilterNormalize}
+
+// lavfiFilters are the ffmpeg filters behind each name, labelled so they can be
+// recognised in mpv's af list
+var lavfiFilters = map[string]string{
+	// Cut pauses longer than half a second, leaving the start of the file alone
+	FilterSkipSilence: "@silence:lavfi=[silenceremove=start_periods=0:stop_periods=-1:stop_duration=0.5:stop_threshold=-45dB]",
+	// Even out loud and quiet passages and speakers
+	FilterNormalize: "@normalize:lavfi=[dynaudnorm=f=250:g=15:p=0.9]",
+	// Cut rumble and lift the range where speech is intelligible
+	FilterVoiceBoost: "@voice:lavfi=[highpass=f=90],@voice-eq:lavfi=[equalizer=f=3000:t=q:w=1.2:g=4]",
+}
+
+// AudioFilters is the processing mpv applies to what it plays
+type AudioFilters struct {
+	SkipSilence bool
+	Normalize   bool
+	VoiceBoost  bool
+}
+
+// ValidFilter reports whether name is one of FilterNames
+func ValidFilter(name string) bool {
+	_, ok := lavfiFilters[name]
+	return ok
+}
+
+// Enabled reports whether the filter called name is on
+func (f AudioFilters) Enabled(name string) bool {
+	switch name {
+	case FilterSkipSilence:
+		return f.SkipSilence
+	case FilterNormalize:
+		return f.Normalize
+	case FilterVoiceBoost:
+		return f.VoiceBoost
+	}
+	return false
+}
+
+// Set turns the filter called name on or off
+func (f *AudioFilters) Set(name string, on bool) error {
+	switch name {
+	case FilterSkipSilence:
+		f.SkipSilence = on
+	case FilterNormalize:
+		f.Normalize = on
+	case FilterVoiceBoost:
+		f.VoiceBoost = on
+	default:
+		return fmt.Errorf("unknown audio filter %q", name)
+	}
+	return nil
+}
+
+// Names returns the names of the filters that are on
+func (f AudioFilters) Names() []string {
+	var names []string
+	for _, name := range FilterNames {
+		if f.Enabled(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// chain returns the value of mpv's af property for the filters that are on
+func (f AudioFilters) chain() string {
+	var chain []string
+	for _, name := range f.Names() {
+		chain = append(chain, lavfiFilters[name])
+	}
+	return strings.Join(chain, ",")
+}
@@ -0,0 +1,61 @@
+package player
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAudioFilters_Chain(t *testing.T) {
+	var filters AudioFilters
+	if chain := filters.chain(); chain != "" {
+		t.Errorf("Expected no filters, got %q", chain)
+	}
+
+	for _, name := range []string{FilterNormalize, FilterSkipSilence} {
+		if err := filters.Set(name, true); err != nil {
+			t.Fatalf("Set(%q) failed: %v", name, err)
+		}
+	}
+	if err := filters.Set("reverb", true); err == nil {
+		t.Error("Expected unknown filters to be refused")
+	}
+
+	chain := filters.chain()
+	if !strings.HasPrefix(chain, "@silence:lavfi=[silenceremove") || !strings.Contains(chain, ",@normalize:lavfi=[dynaudnorm") {
+		t.Errorf("Expected silence removal then normalization, got %q", chain)
+	}
+	if strings.Contains(chain, "@voice") {
+		t.Errorf("Expected the voice filter to be off, got %q", chain)
+	}
+	if names := filters.Names(); len(names) != 2 || names[0] != FilterSkipSilence {
+		t.Errorf("Expected the enabled filters in order, got %v", names)
+	}
+}
+
+func TestMPV_SetAudioFilters(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+
+	if af := f.property("af"); af != "" {
+		t.Errorf("Expected no filters on connecting, got %v", af)
+	}
+
+	filters := AudioFilters{VoiceBoost: true}
+	if err := p.SetAudioFilters(filters); err != nil {
+		t.Fatalf("SetAudioFilters failed: %v", err)
+	}
+	if af := f.property("af"); af != filters.chain() {
+		t.Errorf("Expected af set to %q, got %v", filters.chain(), af)
+	}
+
+	// A chain mpv can't build leaves the filters as they were
+	f.mu.Lock()
+	f.refused = map[string]bool{"af": true}
+	f.mu.Unlock()
+	if err := p.SetAudioFilters(AudioFilters{Normalize: true}); err == nil {
+		t.Error("Expected the refused filters to fail")
+	}
+	if got := p.GetAudioFilters(); got != filters {
+		t.Errorf("Expected the previous filters kept, got %+v", got)
+	}
+}
@@ -0,0 +1,217 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// commandTimeout bounds how long a command waits for mpv's reply
+const commandTimeout = 2 * time.Second
+
+// errIPCClosed is returned for commands sent after the connection to mpv closed
+var errIPCClosed = errors.New("mpv connection closed")
+
+type mpvCommand struct {
+	// Command is a list of arguments, or a map of named arguments
+	Command   interface{} `json:"command"`
+	RequestID int         `json:"request_id,omitempty"`
+}
+
+type mpvResponse struct {
+	Data      interface{} `json:"data"`
+	RequestID int         `json:"request_id"`
+	Error     string      `json:"error"`
+}
+
+type mpvEvent struct {
+	Event  string      `json:"event"`
+	ID     int         `json:"id,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+	Reason string      `json:"reason,omitempty"`
+
+	// FileError explains an end-file event with reason "error"
+	FileError string `json:"file_error,omitempty"`
+}
+
+// ipcClient is a single connection to mpv's JSON IPC socket. Commands carry a
+// request ID and may be sent from any goroutine; replies are matched to them
+// by ID, and everything else mpv sends is delivered on Events.
+type ipcClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan mpvResponse
+	err     error
+
+	events chan mpvEvent
+	closed chan struct{}
+}
+
+// dialIPC connects to the mpv socket at path
+func dialIPC(path string) (*ipcClient, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to mpv socket: %w", err)
+	}
+	c := &ipcClient{
+		conn:    conn,
+		pending: make(map[int]chan mpvResponse),
+		events:  make(chan mpvEvent, 64),
+		closed:  make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+// Events delivers mpv's events, in order, until the connection closes
+func (c *ipcClient) Events() <-chan mpvEvent {
+	return c.events
+}
+
+// isClosed reports whether the connection is gone
+func (c *ipcClient) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// command sends a command and waits for mpv's reply to it
+func (c *ipcClient) command(args ...interface{}) (*mpvResponse, error) {
+	return c.request(args, args[0])
+}
+
+// commandNamed sends a command with named arguments, for commands whose
+// positional arguments differ between mpv versions
+func (c *ipcClient) commandNamed(name string, args map[string]interface{}) (*mpvResponse, error) {
+	command := map[string]interface{}{"name": name}
+	for key, value := range args {
+		command[key] = value
+	}
+	return c.request(command, name)
+}
+
+// request sends command and waits for mpv's reply to it
+func (c *ipcClient) request(command interface{}, name interface{}) (*mpvResponse, error) {
+	reply := make(chan mpvResponse, 1)
+
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = reply
+	c.mu.Unlock()
+
+	data, err := json.Marshal(mpvCommand{Command: command, RequestID: id})
+	if err != nil {
+		c.forget(id)
+		return nil, fmt.Errorf("failed to marshal command: %w", err)
+	}
+	data = append(data, '\n')
+
+	c.writeMu.Lock()
+	c.conn.SetWriteDeadline(time.Now().Add(commandTimeout))
+	_, err = c.conn.Write(data)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.forget(id)
+		return nil, fmt.Errorf("failed to write command: %w", err)
+	}
+
+	timer := time.NewTimer(commandTimeout)
+	defer timer.Stop()
+	select {
+	case response := <-reply:
+		if response.Error != "" && response.Error != "success" {
+			return &response, fmt.Errorf("mpv error: %s", response.Error)
+		}
+		return &response, nil
+	case <-c.closed:
+		return nil, errIPCClosed
+	case <-timer.C:
+		c.forget(id)
+		return nil, fmt.Errorf("no reply from mpv to %v", name)
+	}
+}
+
+// observe asks mpv to report every change of property as a "property-change" event
+func (c *ipcClient) observe(id int, property string) error {
+	_, err := c.command("observe_property", id, property)
+	return err
+}
+
+func (c *ipcClient) forget(id int) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// Close closes the connection; pending commands fail with errIPCClosed
+func (c *ipcClient) Close() {
+	c.conn.Close()
+	<-c.closed
+}
+
+// readLoop routes replies to the commands waiting for them and events to Events
+func (c *ipcClient) readLoop() {
+	reader := bufio.NewReader(c.conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			c.shutdown(err)
+			return
+		}
+
+		var event mpvEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			log.Printf("Player: Skipping malformed message from mpv: %v", err)
+			continue
+		}
+		if event.Event != "" {
+			c.events <- event
+			continue
+		}
+
+		var response mpvResponse
+		if err := json.Unmarshal(line, &response); err != nil {
+			continue
+		}
+		c.mu.Lock()
+		reply, ok := c.pending[response.RequestID]
+		delete(c.pending, response.RequestID)
+		c.mu.Unlock()
+		if ok {
+			reply <- response
+		}
+	}
+}
+
+func (c *ipcClient) shutdown(err error) {
+	c.mu.Lock()
+	c.err = errIPCClosed
+	c.pending = nil
+	c.mu.Unlock()
+
+	c.conn.Close()
+	close(c.events)
+	close(c.closed)
+	if !errors.Is(err, net.ErrClosed) && !errors.Is(err, io.EOF) {
+		log.Printf("Player: Connection to mpv lost: %v", err)
+	}
+}
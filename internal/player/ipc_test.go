@@ -0,0 +1,557 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMPV speaks mpv's JSON IPC protocol on a Unix socket. It replies to every
+// command in order, except "hold", whose reply waits until the next command
+// has been answered.
+type fakeMPV struct {
+	path     string
+	listener net.Listener
+
+	mu          sync.Mutex
+	conn        net.Conn
+	connections int
+	commands    []string
+	properties  map[string]interface{}
+	refused     map[string]bool        // properties set_property fails on
+	named       map[string]interface{} // the last command with named arguments
+	held        *mpvCommand
+	onQuit      func()
+}
+
+func newFakeMPV(t *testing.T) *fakeMPV {
+	t.Helper()
+	path := filepath.Join(privateTempDir(t), "mpv.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakeMPV{path: path, listener: listener, properties: map[string]interface{}{"volume": 50.0}}
+	go f.serve()
+	t.Cleanup(func() {
+		listener.Close()
+		f.closeConn()
+	})
+	return f
+}
+
+// privateTempDir returns a temporary directory only the user can access, as
+// sockets are only trusted in one
+func privateTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0700); err != nil {
+		t.Fatalf("Failed to make %s private: %v", dir, err)
+	}
+	return dir
+}
+
+// TestHelperProcess is not a real test. Other tests run it in place of mpv,
+// serving the IPC protocol on the socket mpv would have created.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("PODCAST_TUI_FAKE_MPV") != "1" {
+		return
+	}
+	for _, arg := range os.Args {
+		if path, ok := strings.CutPrefix(arg, "--input-ipc-server="); ok {
+			listener, err := net.Listen("unix", path)
+			if err != nil {
+				os.Exit(1)
+			}
+			f := &fakeMPV{path: path, listener: listener, onQuit: func() { os.Exit(0) }}
+			f.serve()
+		}
+	}
+	os.Exit(2)
+}
+
+func (f *fakeMPV) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.connections++
+		f.conn = conn
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeMPV) handle(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var cmd mpvCommand
+		if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+			continue
+		}
+		var args []interface{}
+		var flags interface{}
+		switch command := cmd.Command.(type) {
+		case []interface{}:
+			args = command
+			if len(args) > 2 {
+				flags = args[2]
+			}
+		case map[string]interface{}:
+			args = []interface{}{command["name"]}
+			flags = command["flags"]
+			f.mu.Lock()
+			f.named = command
+			f.mu.Unlock()
+		}
+		name, _ := args[0].(string)
+
+		f.mu.Lock()
+		f.commands = append(f.commands, name)
+		if name == "hold" {
+			f.held = &cmd
+			f.mu.Unlock()
+			continue
+		}
+		var data interface{}
+		reply := "success"
+		switch name {
+		case "get_property":
+			data = f.properties[args[1].(string)]
+		case "set_property":
+			if property := args[1].(string); f.refused[property] {
+				reply = "error running command"
+			} else if f.properties != nil {
+				f.properties[property] = args[2]
+			}
+		}
+		held := f.held
+		f.held = nil
+		f.mu.Unlock()
+
+		f.send(mpvResponse{RequestID: cmd.RequestID, Error: reply, Data: data})
+		if held != nil {
+			f.send(mpvResponse{RequestID: held.RequestID, Error: "success", Data: "held"})
+		}
+		switch name {
+		case "loadfile":
+			if flags == "replace" {
+				f.send(mpvEvent{Event: "start-file"})
+				f.send(mpvEvent{Event: "file-loaded"})
+			}
+		case "quit":
+			if f.onQuit != nil {
+				f.onQuit()
+			}
+		}
+	}
+}
+
+// send writes a message to the connected client
+func (f *fakeMPV) send(message interface{}) {
+	data, _ := json.Marshal(message)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn != nil {
+		f.conn.Write(append(data, '\n'))
+	}
+}
+
+func (f *fakeMPV) propertyChange(name string, value interface{}) {
+	f.send(mpvEvent{Event: "property-change", Name: name, Data: value})
+}
+
+func (f *fakeMPV) closeConn() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn != nil {
+		f.conn.Close()
+	}
+}
+
+func (f *fakeMPV) received(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, command := range f.commands {
+		if command == name {
+			count++
+		}
+	}
+	return count
+}
+
+// property returns the last value a property was set to
+func (f *fakeMPV) property(name string) interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.properties[name]
+}
+
+func (f *fakeMPV) connectionCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connections
+}
+
+// eventually polls until cond holds, since events are applied in the background
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// connectedMPV returns an MPV connected to f instead of a real mpv process
+func connectedMPV(t *testing.T, f *fakeMPV) *MPV {
+	t.Helper()
+	p := New()
+	p.socketPath = f.path
+	p.mu.Lock()
+	err := p.connect()
+	p.mu.Unlock()
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(p.Cleanup)
+	return p
+}
+
+func TestIPC_MultiplexesReplies(t *testing.T) {
+	f := newFakeMPV(t)
+	client, err := dialIPC(f.path)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	held := make(chan *mpvResponse)
+	go func() {
+		response, _ := client.command("hold")
+		held <- response
+	}()
+	eventually(t, "the held command", func() bool { return f.received("hold") == 1 })
+
+	f.propertyChange("pause", true)
+	response, err := client.command("get_property", "volume")
+	if err != nil || response.Data != 50.0 {
+		t.Fatalf("Expected the volume while another command waits, got %v, %v", response, err)
+	}
+	if response := <-held; response == nil || response.Data != "held" {
+		t.Errorf("Expected the held reply to reach its own caller, got %v", response)
+	}
+
+	select {
+	case event := <-client.Events():
+		if event.Event != "property-change" || event.Name != "pause" {
+			t.Errorf("Expected the pause change, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the event to be delivered")
+	}
+
+	client.Close()
+	if _, err := client.command("get_property", "volume"); err != errIPCClosed {
+		t.Errorf("Expected commands to fail once closed, got %v", err)
+	}
+}
+
+func TestMPV_ObservesProperties(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+
+	if got := f.received("observe_property"); got != len(observedProperties) {
+		t.Errorf("Expected %d properties observed, got %d", len(observedProperties), got)
+	}
+
+	if err := p.Play("https://example.com/1.mp3"); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	f.propertyChange("duration", 60.0)
+	f.propertyChange("time-pos", 12.5)
+	eventually(t, "the position", func() bool {
+		position, _ := p.GetPosition()
+		duration, _ := p.GetDuration()
+		return position == 12500*time.Millisecond && duration == time.Minute
+	})
+
+	// Pausing in mpv itself is picked up without asking
+	f.propertyChange("pause", true)
+	eventually(t, "the pause", p.IsPaused)
+	f.propertyChange("pause", false)
+	eventually(t, "the resume", p.IsPlaying)
+
+	f.propertyChange("chapter-list", []interface{}{
+		map[string]interface{}{"title": "Intro", "time": 0.0},
+		map[string]interface{}{"title": "Interview", "time": 30.5},
+	})
+	eventually(t, "the chapters", func() bool {
+		chapters := p.Chapters()
+		return len(chapters) == 2 && chapters[1] == Chapter{Title: "Interview", Start: 30500 * time.Millisecond}
+	})
+
+	f.propertyChange("volume", 40.0)
+	f.propertyChange("speed", 1.5)
+	eventually(t, "the volume and speed", func() bool {
+		volume, _ := p.GetVolume()
+		speed, _ := p.GetSpeed()
+		return volume == 40 && speed == 1.5
+	})
+
+	if err := p.Pause(); err != nil || !p.IsPaused() {
+		t.Errorf("Expected Pause to pause, got %v", err)
+	}
+	if err := p.Seek(10); err != nil {
+		t.Errorf("Seek failed: %v", err)
+	}
+	if got := f.connectionCount(); got != 1 {
+		t.Errorf("Expected every command on one connection, got %d connections", got)
+	}
+}
+
+func TestMPV_EndOfFile(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+
+	p.Play("https://example.com/1.mp3")
+	eventually(t, "the file to start", func() bool {
+		p.stateMu.Lock()
+		defer p.stateMu.Unlock()
+		return !p.loading
+	})
+
+	// Replacing or stopping a file is not its end
+	f.send(mpvEvent{Event: "end-file", Reason: "stop"})
+	f.propertyChange("duration", 60.0)
+	f.send(mpvEvent{Event: "end-file", Reason: "eof"})
+	select {
+	case event := <-p.Events():
+		if event.Type != EventEndOfFile {
+			t.Errorf("Expected the end of the file, got %v", event.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an end of file event")
+	}
+	if position, _ := p.GetPosition(); p.GetState() != StateStopped || position != time.Minute {
+		t.Errorf("Expected playback stopped at the end, got %v at %v", p.GetState(), position)
+	}
+
+	// mpv going idle from outside stops playback without finishing the episode
+	p.Play("https://example.com/2.mp3")
+	eventually(t, "the second file", func() bool {
+		p.stateMu.Lock()
+		defer p.stateMu.Unlock()
+		return !p.loading
+	})
+	f.propertyChange("idle-active", true)
+	eventually(t, "playback to stop", func() bool { return p.GetState() == StateStopped })
+	select {
+	case event := <-p.Events():
+		t.Errorf("Expected no event for a stop, got %v", event.Type)
+	default:
+	}
+
+	// Files that fail are reported as errors, keeping the position
+	p.Play("https://example.com/broken.mp3")
+	eventually(t, "the broken file", func() bool {
+		p.stateMu.Lock()
+		defer p.stateMu.Unlock()
+		return !p.loading
+	})
+	f.propertyChange("time-pos", 5.0)
+	f.send(mpvEvent{Event: "end-file", Reason: "error", FileError: "unrecognized file format"})
+	select {
+	case event := <-p.Events():
+		if event.Type != EventError || event.Err == nil || !strings.Contains(event.Err.Error(), "unrecognized file format") {
+			t.Errorf("Expected the file error, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an error event")
+	}
+	if position, _ := p.GetPosition(); p.GetState() != StateStopped || position != 5*time.Second {
+		t.Errorf("Expected playback stopped where it failed, got %v at %v", p.GetState(), position)
+	}
+
+	// Losing mpv stops playback too
+	p.Play("https://example.com/3.mp3")
+	f.closeConn()
+	eventually(t, "the lost connection", func() bool { return p.GetState() == StateStopped })
+}
+
+// nextEvent returns the next event from p, failing after a second
+func nextEvent(t *testing.T, p *MPV) Event {
+	t.Helper()
+	select {
+	case event := <-p.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Expected an event")
+		return Event{}
+	}
+}
+
+func TestMPV_Preload(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+
+	p.Play("https://example.com/1.mp3")
+	if err := p.Preload("https://example.com/2.mp3", 90*time.Second, 0); err != nil {
+		t.Fatalf("Preload failed: %v", err)
+	}
+	f.mu.Lock()
+	named := f.named
+	f.mu.Unlock()
+	options, _ := named["options"].(map[string]interface{})
+	if f.received("playlist-clear") != 1 || named["url"] != "https://example.com/2.mp3" || named["flags"] != "append" || options["start"] != "90.000" {
+		t.Errorf("Expected the file appended to start at 1:30, got %v", named)
+	}
+
+	// The end of the file hands over to the preloaded one
+	f.propertyChange("duration", 60.0)
+	f.send(mpvEvent{Event: "end-file", Reason: "eof"})
+	f.send(mpvEvent{Event: "start-file"})
+	f.propertyChange("playlist-pos", 1.0)
+	if event := nextEvent(t, p); event.Type != EventNextFile || event.URL != "https://example.com/2.mp3" {
+		t.Fatalf("Expected the preloaded file to take over, got %+v", event)
+	}
+	if position, _ := p.GetPosition(); p.GetState() != StatePlaying || position != 90*time.Second {
+		t.Errorf("Expected playing from 1:30, got %v at %v", p.GetState(), position)
+	}
+
+	// mpv may move on before reporting the end of the previous file
+	p.Preload("https://example.com/3.mp3", 0, 0)
+	f.propertyChange("playlist-pos", 0.0)
+	f.propertyChange("playlist-pos", 1.0)
+	f.send(mpvEvent{Event: "end-file", Reason: "eof"})
+	f.send(mpvEvent{Event: "start-file"})
+	if event := nextEvent(t, p); event.Type != EventNextFile || event.URL != "https://example.com/3.mp3" {
+		t.Fatalf("Expected the third file to take over, got %+v", event)
+	}
+
+	// Without a preloaded file the end is reported as usual
+	f.send(mpvEvent{Event: "end-file", Reason: "eof"})
+	if event := nextEvent(t, p); event.Type != EventEndOfFile {
+		t.Errorf("Expected the end of the file, got %+v", event)
+	}
+
+	// A preloaded file that never starts ends the episode when mpv goes idle
+	p.Play("https://example.com/4.mp3")
+	eventually(t, "the fourth file", func() bool {
+		p.stateMu.Lock()
+		defer p.stateMu.Unlock()
+		return !p.loading
+	})
+	p.Preload("https://example.com/gone.mp3", 0, 0)
+	f.send(mpvEvent{Event: "end-file", Reason: "eof"})
+	f.propertyChange("idle-active", true)
+	if event := nextEvent(t, p); event.Type != EventEndOfFile || p.GetState() != StateStopped {
+		t.Errorf("Expected the end of the file, got %+v in state %v", event, p.GetState())
+	}
+
+	// Playing another file drops the preloaded one
+	p.Play("https://example.com/5.mp3")
+	p.Preload("https://example.com/6.mp3", 0, 0)
+	p.Play("https://example.com/7.mp3")
+	f.propertyChange("playlist-pos", 1.0)
+	time.Sleep(50 * time.Millisecond)
+	p.stateMu.Lock()
+	url := p.url
+	p.stateMu.Unlock()
+	if url != "https://example.com/7.mp3" {
+		t.Errorf("Expected the replaced playlist to stay on 7, got %s", url)
+	}
+}
+
+func TestMPV_Outro(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+	loaded := func() bool {
+		p.stateMu.Lock()
+		defer p.stateMu.Unlock()
+		return !p.loading
+	}
+
+	p.Play("https://example.com/1.mp3")
+	eventually(t, "the first file", loaded)
+	p.SetOutro(30 * time.Second)
+	f.propertyChange("duration", 600.0)
+	f.propertyChange("time-pos", 560.0)
+	time.Sleep(50 * time.Millisecond)
+	if p.GetState() != StatePlaying {
+		t.Fatalf("Expected playing before the outro, got %v", p.GetState())
+	}
+
+	// Reaching the outro stops the file as if it had played out
+	f.propertyChange("time-pos", 570.5)
+	if event := nextEvent(t, p); event.Type != EventEndOfFile || p.GetState() != StateStopped {
+		t.Fatalf("Expected the end of the file at the outro, got %+v in state %v", event, p.GetState())
+	}
+	if f.received("stop") != 1 {
+		t.Errorf("Expected mpv stopped at the outro, got %d stops", f.received("stop"))
+	}
+
+	// With a preloaded file mpv moves on to it, which takes its own outro
+	p.Play("https://example.com/2.mp3")
+	eventually(t, "the second file", loaded)
+	p.SetOutro(30 * time.Second)
+	p.Preload("https://example.com/3.mp3", 0, 45*time.Second)
+	f.propertyChange("duration", 600.0)
+	f.propertyChange("time-pos", 575.0)
+	eventually(t, "the skip to the preloaded file", func() bool { return f.received("playlist-next") == 1 })
+	f.send(mpvEvent{Event: "end-file", Reason: "stop"})
+	f.propertyChange("playlist-pos", 1.0)
+	if event := nextEvent(t, p); event.Type != EventNextFile || event.URL != "https://example.com/3.mp3" {
+		t.Fatalf("Expected the preloaded file to take over, got %+v", event)
+	}
+	p.stateMu.Lock()
+	outro := p.outro
+	p.stateMu.Unlock()
+	if outro != 45*time.Second {
+		t.Errorf("Expected the preloaded file's outro, got %v", outro)
+	}
+
+	// Playing a file resets the outro
+	p.Play("https://example.com/4.mp3")
+	eventually(t, "the fourth file", loaded)
+	f.propertyChange("duration", 600.0)
+	f.propertyChange("time-pos", 590.0)
+	time.Sleep(50 * time.Millisecond)
+	if p.GetState() != StatePlaying {
+		t.Errorf("Expected no outro for the fourth file, got %v", p.GetState())
+	}
+}
+
+func TestMPV_SwitchTrackStartsAtPosition(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+
+	if err := p.SwitchTrack("https://example.com/1.mp3", 90*time.Second); err != nil {
+		t.Fatalf("SwitchTrack failed: %v", err)
+	}
+	f.mu.Lock()
+	named := f.named
+	f.mu.Unlock()
+	options, _ := named["options"].(map[string]interface{})
+	if named["flags"] != "replace" || options["start"] != "90.000" {
+		t.Errorf("Expected the file loaded to start at 1:30, got %v", named)
+	}
+	// Until mpv reports otherwise, the position is where the file starts
+	if position, _ := p.GetPosition(); position != 90*time.Second {
+		t.Errorf("Expected the start position reported, got %v", position)
+	}
+	if f.received("seek") != 0 {
+		t.Errorf("Expected no separate seek, got %d", f.received("seek"))
+	}
+}
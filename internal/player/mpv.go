@@ -0,0 +1,1167 @@
+package player
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// observedProperties are mirrored from mpv as they change, so reading them
+// never needs a round trip and changes made outside the app show up at once
+var observedProperties = []string{"time-pos", "duration", "pause", "volume", "speed", "mute", "idle-active", "chapter-list", "playlist-pos"}
+
+// mpv is restarted after a crash at most maxRestarts times in restartWindow,
+// so a file that crashes it every time is not retried forever
+const (
+	maxRestarts   = 3
+	restartWindow = time.Minute
+)
+
+// MPV plays episodes with an mpv process, controlled through its JSON IPC socket
+type MPV struct {
+	// mu serializes starting, stopping and commanding mpv, and is held while
+	// waiting for mpv's replies
+	mu         sync.Mutex
+	binary     []string
+	cmd        *exec.Cmd
+	exited     chan struct{}
+	ipc        *ipcClient
+	socketPath string
+	stopCh     chan struct{}
+	restarts   []time.Time
+
+	// stateMu guards the playback state, which mpv's events update. It is never
+	// held while talking to mpv, so events are not stuck behind a command.
+	stateMu  sync.Mutex
+	url      string
+	state    PlayerState
+	position time.Duration
+	duration time.Duration
+	volume   int
+	speed    float64
+	isMuted  bool
+	filters  AudioFilters
+	rewind   SmartRewind
+	pausedAt time.Time
+	loading  bool
+	chapters []Chapter
+
+	// interrupted is the state playback was in when the connection to mpv was
+	// lost, kept for resuming once mpv is restarted
+	interrupted PlayerState
+
+	// next is the file appended to mpv's playlist by Preload, at playlist
+	// index nextIndex. handover is set once the current file has ended and
+	// mpv is moving on to it.
+	next      string
+	nextStart time.Duration
+	nextOutro time.Duration
+	nextIndex int
+	handover  bool
+
+	// outro is how long before its end the current file counts as ended, see
+	// SetOutro; outroReached is set once it was
+	outro        time.Duration
+	outroReached bool
+
+	progressCh chan Progress
+	events     chan Event
+}
+
+// New returns an mpv backend; mpv itself is started on first use or by StartIdle
+func New() *MPV {
+	return &MPV{
+		progressCh: make(chan Progress, 1),
+		events:     make(chan Event, eventBuffer),
+		binary:     []string{"mpv"},
+		volume:     100,
+		speed:      1.0,
+		state:      StateStopped,
+	}
+}
+
+// StartIdle starts mpv in idle mode, ready to play tracks instantly
+func (p *MPV) StartIdle() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running() {
+		return nil
+	}
+	if err := p.start(); err != nil {
+		return fmt.Errorf("failed to start mpv in idle mode: %w", err)
+	}
+
+	log.Println("mpv started in idle mode, ready for instant playback")
+	return nil
+}
+
+// running reports whether an mpv is connected and able to take commands
+func (p *MPV) running() bool {
+	return p.ipc != nil && !p.ipc.isClosed()
+}
+
+// start launches mpv idle and connects to it. Called with mu held.
+func (p *MPV) start() error {
+	// Reap an mpv whose connection was lost
+	if p.cmd != nil || p.ipc != nil {
+		p.stop()
+	}
+
+	// The socket lives in a directory private to the user, where instances
+	// that crashed may have left theirs behind
+	if p.socketPath == "" {
+		dir, err := socketDir()
+		if err != nil {
+			return fmt.Errorf("failed to start player: %w", err)
+		}
+		removeStaleSockets(dir)
+		p.socketPath = socketPath(dir, os.Getpid())
+	}
+	os.Remove(p.socketPath)
+
+	args := append(p.binary[1:len(p.binary):len(p.binary)],
+		"--no-video",
+		"--really-quiet",
+		"--no-terminal",
+		fmt.Sprintf("--input-ipc-server=%s", p.socketPath),
+		"--idle",
+		"--force-window=no",
+		"--keep-open=no",          // Ensure mpv goes idle when file ends
+		"--prefetch-playlist=yes", // Open preloaded streams before they play
+	)
+	cmd := exec.Command(p.binary[0], args...)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start player: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+	p.cmd = cmd
+	p.exited = exited
+
+	if err := p.connect(); err != nil {
+		cmd.Process.Kill()
+		<-exited
+		p.cmd = nil
+		return err
+	}
+
+	go p.watchProcess(cmd, exited)
+	return nil
+}
+
+// watchProcess restarts mpv if it exits on its own, and resumes the file that
+// was playing where it was. Exits caused by stop are expected and ignored.
+func (p *MPV) watchProcess(cmd *exec.Cmd, exited chan struct{}) {
+	<-exited
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cmd != cmd {
+		return
+	}
+
+	crash := fmt.Errorf("mpv exited unexpectedly (%v)", cmd.ProcessState)
+	log.Printf("Player: %v", crash)
+
+	p.stateMu.Lock()
+	url, position, state, outro := p.url, p.position, p.state, p.outro
+	if p.interrupted != StateStopped {
+		state = p.interrupted
+	}
+	p.interrupted = StateStopped
+	p.stateMu.Unlock()
+	wasPlaying := url != "" && state != StateStopped
+
+	p.stop()
+
+	if !p.allowRestart() {
+		log.Printf("Player: Not restarting mpv, it crashed %d times in %v", maxRestarts, restartWindow)
+		if wasPlaying {
+			sendEvent(p.events, Event{Type: EventError, Err: fmt.Errorf("%v, giving up after %d restarts", crash, maxRestarts)})
+		}
+		return
+	}
+
+	if err := p.start(); err != nil {
+		log.Printf("Player: Failed to restart mpv: %v", err)
+		if wasPlaying {
+			sendEvent(p.events, Event{Type: EventError, Err: fmt.Errorf("%v and could not be restarted: %w", crash, err)})
+		}
+		return
+	}
+	if !wasPlaying {
+		log.Println("Player: mpv restarted in idle mode")
+		return
+	}
+
+	if err := p.load(url, position, state == StatePaused); err != nil {
+		sendEvent(p.events, Event{Type: EventError, Err: fmt.Errorf("%v and could not resume: %w", crash, err)})
+		return
+	}
+	// Loading resets the outro, but it's still the same episode
+	p.stateMu.Lock()
+	p.outro = outro
+	p.stateMu.Unlock()
+	log.Printf("Player: mpv restarted, resuming %s at %v", url, position)
+	sendEvent(p.events, Event{Type: EventRecovered, Err: crash})
+}
+
+// allowRestart records a restart unless there were too many recently.
+// Called with mu held.
+func (p *MPV) allowRestart() bool {
+	now := time.Now()
+	recent := p.restarts[:0]
+	for _, restart := range p.restarts {
+		if now.Sub(restart) < restartWindow {
+			recent = append(recent, restart)
+		}
+	}
+	p.restarts = recent
+
+	if len(p.restarts) >= maxRestarts {
+		return false
+	}
+	p.restarts = append(p.restarts, now)
+	return true
+}
+
+// connect opens the one connection used for everything to the mpv listening
+// on socketPath, carries over volume, speed and mute, and subscribes to the
+// observed properties. Called with mu held.
+func (p *MPV) connect() error {
+	var client *ipcClient
+	var err error
+	// mpv needs a moment to create the socket, which must be ours to trust it
+	for i := 0; i < 10; i++ {
+		err = checkSocket(p.socketPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("refusing to use mpv socket: %w", err)
+		}
+		if err == nil {
+			if client, err = dialIPC(p.socketPath); err == nil {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		return fmt.Errorf("mpv socket not ready: %w", err)
+	}
+
+	go p.handleEvents(client)
+
+	p.stateMu.Lock()
+	volume, speed, muted, filters := p.volume, p.speed, p.isMuted, p.filters
+	p.stateMu.Unlock()
+	for _, property := range []struct {
+		name  string
+		value interface{}
+	}{{"volume", volume}, {"speed", speed}, {"mute", muted}, {"af", filters.chain()}} {
+		if _, err := client.command("set_property", property.name, property.value); err != nil {
+			log.Printf("Player: Failed to set %s: %v", property.name, err)
+		}
+	}
+
+	for i, name := range observedProperties {
+		if err := client.observe(i+1, name); err != nil {
+			client.Close()
+			return fmt.Errorf("failed to observe %s: %w", name, err)
+		}
+	}
+
+	p.ipc = client
+	p.stopCh = make(chan struct{})
+	go p.reportProgress(p.stopCh)
+	return nil
+}
+
+// command sends a command to the running mpv. Called with mu held.
+func (p *MPV) command(args ...interface{}) (*mpvResponse, error) {
+	if p.ipc == nil {
+		return nil, errIPCClosed
+	}
+	return p.ipc.command(args...)
+}
+
+// commandNamed sends a command with named arguments. Called with mu held.
+func (p *MPV) commandNamed(name string, args map[string]interface{}) (*mpvResponse, error) {
+	if p.ipc == nil {
+		return nil, errIPCClosed
+	}
+	return p.ipc.commandNamed(name, args)
+}
+
+// SwitchTrack switches to a new track without stopping mpv
+func (p *MPV) SwitchTrack(url string, start time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running() {
+		if err := p.start(); err != nil {
+			return err
+		}
+	}
+
+	return p.load(url, start, false)
+}
+
+func (p *MPV) Play(url string) error {
+	return p.SwitchTrack(url, 0)
+}
+
+// load replaces the current file with url, starting at position, which is
+// reported until mpv starts the file. Called with mu held.
+func (p *MPV) load(url string, position time.Duration, paused bool) error {
+	// Until mpv starts the new file, events are about the old one
+	p.stateMu.Lock()
+	p.url = url
+	p.position = position
+	p.duration = 0
+	p.chapters = nil
+	p.loading = true
+	p.next = ""
+	p.handover = false
+	p.outro = 0
+	p.outroReached = false
+	p.stateMu.Unlock()
+
+	// mpv starts the file at position itself, rather than from the beginning
+	// until a seek, so the position is right from the first report
+	_, err := p.commandNamed("loadfile", map[string]interface{}{
+		"url":     url,
+		"flags":   "replace",
+		"options": map[string]string{"start": fmt.Sprintf("%.3f", position.Seconds())},
+	})
+	if err != nil {
+		p.resetState()
+		return fmt.Errorf("failed to load file: %w", err)
+	}
+
+	if _, err := p.command("set_property", "pause", paused); err != nil {
+		log.Printf("Warning: failed to set pause after loading file: %v", err)
+	}
+
+	p.stateMu.Lock()
+	p.state = StatePlaying
+	if paused {
+		p.state = StatePaused
+	}
+	p.stateMu.Unlock()
+	return nil
+}
+
+// Preload appends url to mpv's playlist behind the current file, which is
+// left as the only other entry, so mpv moves on to it when the current file
+// ends. The start position is passed as a per-file option, so it begins there
+// without a seek.
+func (p *MPV) Preload(url string, start, outro time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.running() || p.GetState() == StateStopped {
+		return nil
+	}
+
+	// Set first: an end of file from here on is a handover to url
+	p.stateMu.Lock()
+	p.next = url
+	p.nextStart = start
+	p.nextOutro = outro
+	p.nextIndex = 1
+	p.stateMu.Unlock()
+
+	if _, err := p.command("playlist-clear"); err != nil {
+		p.cancelPreload()
+		return fmt.Errorf("failed to clear playlist: %w", err)
+	}
+	if url == "" {
+		return nil
+	}
+
+	options := map[string]interface{}{}
+	if start > 0 {
+		options["start"] = fmt.Sprintf("%.3f", start.Seconds())
+	}
+	if _, err := p.commandNamed("loadfile", map[string]interface{}{"url": url, "flags": "append", "options": options}); err != nil {
+		p.cancelPreload()
+		return fmt.Errorf("failed to preload file: %w", err)
+	}
+	return nil
+}
+
+// SetOutro sets how long before its end the current file counts as ended.
+// The end is detected from the position mpv reports, see reachedOutro.
+func (p *MPV) SetOutro(outro time.Duration) error {
+	p.stateMu.Lock()
+	p.outro = max(outro, 0)
+	p.outroReached = false
+	atOutro := p.atOutro()
+	p.stateMu.Unlock()
+
+	if atOutro {
+		go p.reachedOutro()
+	}
+	return nil
+}
+
+// atOutro reports whether playback just reached the outro of the current
+// file. Called with stateMu held.
+func (p *MPV) atOutro() bool {
+	if p.outro <= 0 || p.outroReached || p.loading || p.state != StatePlaying {
+		return false
+	}
+	if p.duration <= p.outro || p.position < p.duration-p.outro {
+		return false
+	}
+	p.outroReached = true
+	return true
+}
+
+// reachedOutro ends the current file at its outro as if it had played out:
+// mpv moves on to the preloaded file, or stops and EventEndOfFile is sent
+func (p *MPV) reachedOutro() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stateMu.Lock()
+	if !p.outroReached || p.state == StateStopped {
+		// Something else was played or stopped meanwhile
+		p.stateMu.Unlock()
+		return
+	}
+	log.Printf("Player: Reached the outro of %s", p.url)
+	p.position = p.duration
+	finalProgress := Progress{Position: p.position, Duration: p.duration}
+	preloaded := p.next != ""
+	if preloaded {
+		p.handover = true
+	} else {
+		p.state = StateStopped
+	}
+	p.stateMu.Unlock()
+	p.sendProgress(finalProgress)
+
+	if preloaded {
+		// mpv's end-file for the skipped file is ignored during the handover,
+		// which completes when playlist-pos moves on
+		_, err := p.command("playlist-next")
+		if err == nil {
+			return
+		}
+		log.Printf("Player: Failed to skip to the preloaded file: %v", err)
+		p.stateMu.Lock()
+		p.next = ""
+		p.handover = false
+		p.state = StateStopped
+		p.stateMu.Unlock()
+	}
+	if _, err := p.command("stop"); err != nil {
+		log.Printf("Player: Failed to stop at the outro: %v", err)
+	}
+	sendEvent(p.events, Event{Type: EventEndOfFile})
+}
+
+func (p *MPV) cancelPreload() {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	p.next = ""
+}
+
+func (p *MPV) Pause() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.GetState() != StatePlaying {
+		return nil
+	}
+
+	if _, err := p.command("set_property", "pause", true); err != nil {
+		return fmt.Errorf("failed to pause: %w", err)
+	}
+
+	p.setState(StatePlaying, StatePaused)
+	p.stateMu.Lock()
+	p.pausedAt = time.Now()
+	p.stateMu.Unlock()
+	return nil
+}
+
+// Resume unpauses, first going back by the smart rewind for the time spent paused
+func (p *MPV) Resume() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.GetState() != StatePaused {
+		return nil
+	}
+
+	p.stateMu.Lock()
+	var rewind time.Duration
+	if !p.pausedAt.IsZero() {
+		rewind = p.rewind.Amount(time.Since(p.pausedAt))
+	}
+	target := max(p.position-rewind, 0)
+	p.stateMu.Unlock()
+
+	if rewind > 0 {
+		if _, err := p.command("seek", target.Seconds(), "absolute"); err != nil {
+			log.Printf("Player: Failed to rewind after pause: %v", err)
+		} else {
+			p.stateMu.Lock()
+			p.position = target
+			p.stateMu.Unlock()
+		}
+	}
+
+	if _, err := p.command("set_property", "pause", false); err != nil {
+		return fmt.Errorf("failed to resume: %w", err)
+	}
+
+	p.setState(StatePaused, StatePlaying)
+	p.stateMu.Lock()
+	p.pausedAt = time.Time{}
+	p.stateMu.Unlock()
+	return nil
+}
+
+// SetSmartRewind sets how far Resume goes back after a pause
+func (p *MPV) SetSmartRewind(rewind SmartRewind) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	p.rewind = rewind
+}
+
+// setState moves from one state to another, unless an event already changed it
+func (p *MPV) setState(from, to PlayerState) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	if p.state == from {
+		p.state = to
+	}
+}
+
+// resetState forgets the current file
+func (p *MPV) resetState() {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	p.state = StateStopped
+	p.url = ""
+	p.position = 0
+	p.duration = 0
+	p.chapters = nil
+	p.loading = false
+	p.next = ""
+	p.handover = false
+	p.outro = 0
+	p.outroReached = false
+}
+
+func (p *MPV) TogglePause() error {
+	if p.GetState() == StatePaused {
+		return p.Resume()
+	}
+	return p.Pause()
+}
+
+func (p *MPV) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stop()
+}
+
+// Cleanup ensures all resources are properly released
+// This should be called when the application is shutting down
+func (p *MPV) Cleanup() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Force stop if still running
+	if p.cmd != nil || p.ipc != nil {
+		p.stop()
+	}
+
+	// Final cleanup of socket file
+	if p.socketPath != "" {
+		os.Remove(p.socketPath)
+	}
+}
+
+// StopKeepIdle stops playback but keeps mpv running in idle mode
+func (p *MPV) StopKeepIdle() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.GetState() == StateStopped {
+		return nil
+	}
+
+	if _, err := p.command("stop"); err != nil {
+		// If command fails, fallback to full stop
+		return p.stop()
+	}
+
+	p.resetState()
+	return nil
+}
+
+func (p *MPV) stop() error {
+	p.resetState()
+
+	if p.cmd == nil && p.ipc == nil {
+		return nil
+	}
+
+	// Signal the progress reporter to stop
+	if p.stopCh != nil {
+		close(p.stopCh)
+		p.stopCh = nil
+	}
+
+	if p.cmd != nil && p.cmd.Process != nil {
+		// Try graceful quit first; mpv may close the connection before replying
+		p.command("quit")
+
+		// Give it a moment to quit gracefully
+		select {
+		case <-p.exited:
+			// Process exited gracefully
+		case <-time.After(500 * time.Millisecond):
+			// Force kill if not exited
+			log.Printf("Force killing mpv process (pid: %d)", p.cmd.Process.Pid)
+			if err := p.cmd.Process.Kill(); err != nil {
+				log.Printf("Error killing mpv process: %v", err)
+			}
+			<-p.exited // Wait for process to exit
+		}
+	}
+
+	if p.ipc != nil {
+		p.ipc.Close()
+		p.ipc = nil
+	}
+
+	// Clean up socket file - try multiple times in case it's still in use
+	if p.cmd != nil {
+		for i := 0; i < 3; i++ {
+			if err := os.Remove(p.socketPath); err == nil || os.IsNotExist(err) {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	p.cmd = nil
+	log.Printf("Player stopped and cleaned up")
+	return nil
+}
+
+func (p *MPV) Seek(seconds int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stateMu.Lock()
+	state, position, duration := p.state, p.position, p.duration
+	p.stateMu.Unlock()
+
+	if state == StateStopped {
+		return nil
+	}
+
+	// For resuming playback, use absolute seek if the value is large
+	// This assumes values > 300 (5 minutes) are absolute positions for resuming
+	seekType := "relative"
+	if seconds > 300 {
+		seekType = "absolute"
+	}
+
+	// For relative seeks, check bounds
+	if seekType == "relative" {
+		newPosition := position + time.Duration(seconds)*time.Second
+
+		// Check if seek would go before start
+		if newPosition < 0 {
+			// Seek to beginning instead
+			if _, err := p.command("seek", 0, "absolute"); err != nil {
+				return fmt.Errorf("failed to seek to beginning: %w", err)
+			}
+			return nil
+		}
+
+		// Check if seek would go past end
+		if duration > 0 && newPosition > duration {
+			// Seek to near end instead (1 second before end)
+			targetSeconds := int(duration.Seconds()) - 1
+			if targetSeconds < 0 {
+				targetSeconds = 0
+			}
+			if _, err := p.command("seek", targetSeconds, "absolute"); err != nil {
+				return fmt.Errorf("failed to seek to end: %w", err)
+			}
+			return nil
+		}
+	}
+
+	if _, err := p.command("seek", seconds, seekType); err != nil {
+		return fmt.Errorf("failed to seek: %w", err)
+	}
+
+	return nil
+}
+
+// SeekAbsolute seeks to an absolute position in seconds
+func (p *MPV) SeekAbsolute(seconds int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stateMu.Lock()
+	state, duration := p.state, p.duration
+	p.stateMu.Unlock()
+
+	if state == StateStopped {
+		return nil
+	}
+
+	// Bounds checking
+	if seconds < 0 {
+		seconds = 0
+	} else if duration > 0 {
+		maxSeconds := int(duration.Seconds())
+		if seconds >= maxSeconds {
+			// Don't seek past the end - stop 1 second before
+			seconds = maxSeconds - 1
+			if seconds < 0 {
+				seconds = 0
+			}
+		}
+	}
+
+	if _, err := p.command("seek", seconds, "absolute"); err != nil {
+		return fmt.Errorf("failed to seek: %w", err)
+	}
+
+	return nil
+}
+
+// Volume control methods
+func (p *MPV) GetVolume() (int, error) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.volume, nil
+}
+
+func (p *MPV) SetVolume(volume int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if volume < 0 {
+		volume = 0
+	} else if volume > 100 {
+		volume = 100
+	}
+
+	p.stateMu.Lock()
+	p.volume = volume
+	p.stateMu.Unlock()
+
+	if !p.running() {
+		return nil
+	}
+
+	if _, err := p.command("set_property", "volume", volume); err != nil {
+		return fmt.Errorf("failed to set volume: %w", err)
+	}
+
+	return nil
+}
+
+// Speed control methods
+func (p *MPV) GetSpeed() (float64, error) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.speed, nil
+}
+
+func (p *MPV) SetSpeed(speed float64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if speed < 0.25 {
+		speed = 0.25
+	} else if speed > 4.0 {
+		speed = 4.0
+	}
+
+	p.stateMu.Lock()
+	p.speed = speed
+	p.stateMu.Unlock()
+
+	if !p.running() {
+		return nil
+	}
+
+	if _, err := p.command("set_property", "speed", speed); err != nil {
+		return fmt.Errorf("failed to set speed: %w", err)
+	}
+
+	return nil
+}
+
+// Mute control
+func (p *MPV) IsMuted() bool {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.isMuted
+}
+
+func (p *MPV) ToggleMute() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stateMu.Lock()
+	p.isMuted = !p.isMuted
+	muted := p.isMuted
+	p.stateMu.Unlock()
+
+	if !p.running() {
+		return nil
+	}
+
+	if _, err := p.command("set_property", "mute", muted); err != nil {
+		return fmt.Errorf("failed to toggle mute: %w", err)
+	}
+
+	return nil
+}
+
+// SetAudioFilters sets mpv's af property to the filters that are on. If mpv
+// refuses them, such as when it was built without lavfi, the filters in use
+// are left as they were.
+func (p *MPV) SetAudioFilters(filters AudioFilters) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stateMu.Lock()
+	previous := p.filters
+	p.filters = filters
+	p.stateMu.Unlock()
+
+	if !p.running() {
+		return nil
+	}
+
+	if _, err := p.command("set_property", "af", filters.chain()); err != nil {
+		p.stateMu.Lock()
+		p.filters = previous
+		p.stateMu.Unlock()
+		return fmt.Errorf("failed to set audio filters: %w", err)
+	}
+
+	return nil
+}
+
+func (p *MPV) GetAudioFilters() AudioFilters {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.filters
+}
+
+// Progress tracking methods
+func (p *MPV) GetPosition() (time.Duration, error) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.position, nil
+}
+
+func (p *MPV) GetDuration() (time.Duration, error) {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.duration, nil
+}
+
+func (p *MPV) IsPlaying() bool {
+	return p.GetState() == StatePlaying
+}
+
+func (p *MPV) IsPaused() bool {
+	return p.GetState() == StatePaused
+}
+
+func (p *MPV) GetState() PlayerState {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.state
+}
+
+func (p *MPV) Chapters() []Chapter {
+	p.stateMu.Lock()
+	defer p.stateMu.Unlock()
+	return p.chapters
+}
+
+func (p *MPV) Progress() <-chan Progress {
+	return p.progressCh
+}
+
+func (p *MPV) Events() <-chan Event {
+	return p.events
+}
+
+// sendProgress reports progress without blocking if the last report is unread
+func (p *MPV) sendProgress(progress Progress) {
+	select {
+	case p.progressCh <- progress:
+	default:
+	}
+}
+
+// reportProgress sends the observed position once a second while a file is
+// loaded; it reads the mirrored state and never queries mpv
+func (p *MPV) reportProgress(stop chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.stateMu.Lock()
+			state := p.state
+			progress := Progress{Position: p.position, Duration: p.duration}
+			p.stateMu.Unlock()
+
+			if state != StateStopped {
+				p.sendProgress(progress)
+			}
+		}
+	}
+}
+
+// handleEvents applies mpv's events until the connection closes
+func (p *MPV) handleEvents(client *ipcClient) {
+	for event := range client.Events() {
+		switch event.Event {
+		case "property-change":
+			p.propertyChanged(event.Name, event.Data)
+		case "start-file":
+			p.stateMu.Lock()
+			p.loading = false
+			p.stateMu.Unlock()
+		case "end-file":
+			p.fileEnded(event.Reason, event.FileError)
+		}
+	}
+
+	// The connection closes when mpv is stopped, which resets the state first,
+	// or when mpv goes away on its own. Once mpv was replaced the state is
+	// about the new one.
+	p.mu.Lock()
+	current := p.ipc == client
+	p.mu.Unlock()
+	if !current {
+		return
+	}
+
+	p.stateMu.Lock()
+	wasActive := p.state != StateStopped
+	if wasActive {
+		p.interrupted = p.state
+	}
+	p.state = StateStopped
+	p.stateMu.Unlock()
+	if wasActive {
+		log.Printf("Player: Lost connection to mpv while playing")
+	}
+}
+
+// propertyChanged mirrors an observed property. Pausing and stopping are
+// reported as progress straight away, so they show even when done in mpv.
+func (p *MPV) propertyChanged(name string, data interface{}) {
+	p.stateMu.Lock()
+	changed := false
+	var event *Event
+	switch name {
+	case "time-pos":
+		// Until mpv starts the new file, positions are the old one's
+		if pos, ok := data.(float64); ok && pos >= 0 && !p.loading {
+			p.position = time.Duration(pos * float64(time.Second))
+			if p.atOutro() {
+				go p.reachedOutro()
+			}
+		}
+	case "duration":
+		if dur, ok := data.(float64); ok && dur > 0 {
+			p.duration = time.Duration(dur * float64(time.Second))
+		}
+	case "pause":
+		paused, ok := data.(bool)
+		if ok && paused && p.state == StatePlaying {
+			p.state = StatePaused
+			p.pausedAt = time.Now()
+			changed = true
+		} else if ok && !paused && p.state == StatePaused {
+			p.state = StatePlaying
+			changed = true
+		}
+	case "volume":
+		if vol, ok := data.(float64); ok {
+			p.volume = int(math.Round(vol))
+		}
+	case "speed":
+		if speed, ok := data.(float64); ok {
+			p.speed = speed
+		}
+	case "mute":
+		if muted, ok := data.(bool); ok {
+			p.isMuted = muted
+		}
+	case "chapter-list":
+		p.chapters = parseChapters(data)
+	case "idle-active":
+		idle, ok := data.(bool)
+		if ok && idle && p.handover {
+			// The preloaded file never started, so the episode just ended
+			p.next = ""
+			p.handover = false
+			p.state = StateStopped
+			event = &Event{Type: EventEndOfFile}
+			changed = true
+		} else if ok && idle && !p.loading && p.state != StateStopped {
+			// Going idle with no file being loaded means playback was stopped from outside
+			p.state = StateStopped
+			changed = true
+		}
+	case "playlist-pos":
+		if pos, ok := data.(float64); ok && p.next != "" && int(pos) == p.nextIndex {
+			event = p.nextFileStarted()
+			changed = true
+		}
+	}
+	progress := Progress{Position: p.position, Duration: p.duration}
+	p.stateMu.Unlock()
+
+	if changed {
+		p.sendProgress(progress)
+	}
+	if event != nil {
+		sendEvent(p.events, *event)
+	}
+}
+
+// nextFileStarted makes the preloaded file the current one when mpv's
+// playlist moves to it. mpv may report the move before the end of the
+// previous file, which is then ignored. Called with stateMu held.
+func (p *MPV) nextFileStarted() *Event {
+	log.Printf("Player: Moved on to preloaded file %s", p.next)
+	if !p.handover {
+		p.loading = true
+	}
+	p.url = p.next
+	p.position = p.nextStart
+	p.duration = 0
+	p.chapters = nil
+	p.state = StatePlaying
+	p.outro = p.nextOutro
+	p.outroReached = false
+	p.next = ""
+	p.handover = false
+	return &Event{Type: EventNextFile, URL: p.url}
+}
+
+// fileEnded handles mpv's end-file event. Files replaced by loading another,
+// or stopped, end too, but only "eof" means the episode played to its end.
+// On "error" the position is left alone and the failure is reported instead.
+func (p *MPV) fileEnded(reason, fileError string) {
+	log.Printf("Player: Received end-file event (reason: %s, error: %s)", reason, fileError)
+
+	p.stateMu.Lock()
+	if p.loading {
+		// About a file that was already replaced
+		p.stateMu.Unlock()
+		return
+	}
+	switch reason {
+	case "", "eof":
+	case "error":
+		p.state = StateStopped
+		preloaded := p.next != ""
+		p.next = ""
+		p.handover = false
+		p.stateMu.Unlock()
+		if preloaded {
+			// mpv moves on to the preloaded file, but a failure stops the queue
+			go p.stopPlaylist()
+		}
+		if fileError == "" {
+			fileError = "unknown error"
+		}
+		sendEvent(p.events, Event{Type: EventError, Err: fmt.Errorf("playback failed: %s", fileError)})
+		return
+	default:
+		p.stateMu.Unlock()
+		return
+	}
+
+	// Send final progress with position = duration
+	if p.duration > 0 {
+		p.position = p.duration
+	}
+	finalProgress := Progress{
+		Position: p.position,
+		Duration: p.duration,
+	}
+	if p.next != "" {
+		// mpv goes on with the preloaded file, see nextFileStarted
+		p.handover = true
+		p.stateMu.Unlock()
+		p.sendProgress(finalProgress)
+		return
+	}
+	p.state = StateStopped
+	p.stateMu.Unlock()
+
+	p.sendProgress(finalProgress)
+	sendEvent(p.events, Event{Type: EventEndOfFile})
+}
+
+// stopPlaylist stops mpv after the current file failed, unless something was
+// played since
+func (p *MPV) stopPlaylist() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.GetState() != StateStopped || !p.running() {
+		return
+	}
+	if _, err := p.command("stop"); err != nil {
+		log.Printf("Player: Failed to stop after a playback error: %v", err)
+	}
+}
+
+// parseChapters reads mpv's chapter-list, a list of objects with a title and
+// a start time in seconds
+func parseChapters(data interface{}) []Chapter {
+	list, _ := data.([]interface{})
+	var chapters []Chapter
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		start, _ := entry["time"].(float64)
+		title, _ := entry["title"].(string)
+		chapters = append(chapters, Chapter{Title: title, Start: time.Duration(start * float64(time.Second))})
+	}
+	return chapters
+}
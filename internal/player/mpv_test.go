@@ -0,0 +1,104 @@
+package player
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// helperMPV returns an MPV that runs TestHelperProcess instead of mpv
+func helperMPV(t *testing.T) *MPV {
+	t.Helper()
+	t.Setenv("PODCAST_TUI_FAKE_MPV", "1")
+	p := New()
+	p.binary = []string{os.Args[0], "-test.run=^TestHelperProcess$", "--"}
+	p.socketPath = socketPath(privateTempDir(t), os.Getpid())
+	t.Cleanup(p.Cleanup)
+	return p
+}
+
+// crash kills mpv and returns the next event
+func crash(t *testing.T, p *MPV) Event {
+	t.Helper()
+	p.mu.Lock()
+	cmd := p.cmd
+	p.mu.Unlock()
+	if cmd == nil {
+		t.Fatal("Expected mpv to be running")
+	}
+	cmd.Process.Kill()
+
+	select {
+	case event := <-p.Events():
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an event after the crash")
+		return Event{}
+	}
+}
+
+func TestMPV_RestartsAfterCrash(t *testing.T) {
+	p := helperMPV(t)
+	if err := p.Play("https://example.com/1.mp3"); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	p.stateMu.Lock()
+	p.position = 90 * time.Second
+	p.stateMu.Unlock()
+	p.SetOutro(30 * time.Second)
+	if err := p.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+
+	event := crash(t, p)
+	if event.Type != EventRecovered || event.Err == nil {
+		t.Fatalf("Expected recovery, got %+v", event)
+	}
+	position, _ := p.GetPosition()
+	if p.url != "https://example.com/1.mp3" || position != 90*time.Second || !p.IsPaused() {
+		t.Errorf("Expected to resume paused at 1m30s, got %q at %v in state %v", p.url, position, p.GetState())
+	}
+	if !p.running() {
+		t.Error("Expected a new mpv to be connected")
+	}
+	p.stateMu.Lock()
+	outro := p.outro
+	p.stateMu.Unlock()
+	if outro != 30*time.Second {
+		t.Errorf("Expected the outro kept across the restart, got %v", outro)
+	}
+
+	// A file that keeps crashing mpv is given up on
+	for i := 1; i < maxRestarts; i++ {
+		if event := crash(t, p); event.Type != EventRecovered {
+			t.Fatalf("Expected restart %d to recover, got %+v", i+1, event)
+		}
+	}
+	event = crash(t, p)
+	if event.Type != EventError || !strings.Contains(event.Err.Error(), "giving up") {
+		t.Fatalf("Expected to give up, got %+v", event)
+	}
+	if p.GetState() != StateStopped || p.running() {
+		t.Errorf("Expected playback stopped with no mpv, got state %v", p.GetState())
+	}
+}
+
+func TestMPV_StopIsNotACrash(t *testing.T) {
+	p := helperMPV(t)
+	if err := p.Play("https://example.com/1.mp3"); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	select {
+	case event := <-p.Events():
+		t.Errorf("Expected no event when stopping, got %+v", event)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if p.running() || len(p.restarts) != 0 {
+		t.Error("Expected mpv to stay stopped")
+	}
+}
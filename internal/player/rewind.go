@@ -0,0 +1,35 @@
+package player
+
+import (
+	"math"
+	"time"
+)
+
+// Pauses shorter than rewindShortPause are not rewound; from rewindLongPause
+// on, SmartRewind rewinds its maximum
+const (
+	rewindShortPause = time.Minute
+	rewindLongPause  = time.Hour
+)
+
+// SmartRewind goes back a little when playback resumes after a pause, to help
+// the listener back into it. The longer the pause, the further back: Min after
+// a minute, growing with the logarithm of the pause to Max after an hour. The
+// zero value never rewinds.
+type SmartRewind struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Amount returns how far back to go after a pause of the given length
+func (r SmartRewind) Amount(paused time.Duration) time.Duration {
+	if r.Max <= 0 || paused < rewindShortPause {
+		return 0
+	}
+	low := min(max(r.Min, 0), r.Max)
+	if paused >= rewindLongPause {
+		return r.Max
+	}
+	scale := math.Log(float64(paused)/float64(rewindShortPause)) / math.Log(float64(rewindLongPause)/float64(rewindShortPause))
+	return (low + time.Duration(scale*float64(r.Max-low))).Round(time.Second)
+}
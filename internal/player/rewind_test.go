@@ -0,0 +1,60 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmartRewind_Amount(t *testing.T) {
+	rewind := SmartRewind{Min: 3 * time.Second, Max: 30 * time.Second}
+	tests := []struct {
+		paused time.Duration
+		want   time.Duration
+	}{
+		{10 * time.Second, 0},
+		{time.Minute, 3 * time.Second},
+		{8 * time.Minute, 17 * time.Second},
+		{time.Hour, 30 * time.Second},
+		{24 * time.Hour, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := rewind.Amount(tt.paused); got != tt.want {
+			t.Errorf("Amount(%v) = %v, want %v", tt.paused, got, tt.want)
+		}
+	}
+
+	if got := (SmartRewind{}).Amount(time.Hour); got != 0 {
+		t.Errorf("Expected the zero value not to rewind, got %v", got)
+	}
+}
+
+func TestMPV_ResumeRewinds(t *testing.T) {
+	f := newFakeMPV(t)
+	p := connectedMPV(t, f)
+	p.SetSmartRewind(SmartRewind{Min: 3 * time.Second, Max: 30 * time.Second})
+
+	p.Play("https://example.com/1.mp3")
+	f.propertyChange("time-pos", 600.0)
+	eventually(t, "the position", func() bool {
+		position, _ := p.GetPosition()
+		return position == 10*time.Minute
+	})
+
+	// A short pause resumes where it was
+	p.Pause()
+	p.Resume()
+	if f.received("seek") != 0 {
+		t.Error("Expected no rewind after a short pause")
+	}
+
+	p.Pause()
+	p.stateMu.Lock()
+	p.pausedAt = time.Now().Add(-2 * time.Hour)
+	p.stateMu.Unlock()
+	if err := p.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if position, _ := p.GetPosition(); f.received("seek") != 1 || position != 9*time.Minute+30*time.Second || !p.IsPlaying() {
+		t.Errorf("Expected to resume 30s back, at %v in state %v", position, p.GetState())
+	}
+}
@@ -0,0 +1,120 @@
+package player
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// socketDir returns a directory only the current user can use for mpv's
+// control socket: podcast-tui under $XDG_RUNTIME_DIR, or under the user cache
+// dir when there is no runtime dir. It is created with mode 0700.
+func socketDir() (string, error) {
+	base := os.Getenv("XDG_RUNTIME_DIR")
+	if info, err := os.Stat(base); base == "" || err != nil || !info.IsDir() {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("no directory for the mpv socket: %w", err)
+		}
+		base = cacheDir
+	}
+
+	dir := filepath.Join(base, "podcast-tui")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := checkPrivateDir(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// socketPath names the socket of the mpv started by process pid
+func socketPath(dir string, pid int) string {
+	return filepath.Join(dir, fmt.Sprintf("mpv-%d.sock", pid))
+}
+
+// checkPrivateDir makes sure dir belongs to the current user and nobody else
+// can reach into it, tightening its mode if needed
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	if !ownedByUser(info) {
+		return fmt.Errorf("%s is owned by another user", dir)
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		if err := os.Chmod(dir, 0700); err != nil {
+			return fmt.Errorf("failed to make %s private: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// checkSocket makes sure path is a socket of the current user in a private
+// directory before it is trusted to be our mpv
+func checkSocket(path string) error {
+	dir, err := os.Lstat(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if !ownedByUser(dir) || dir.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("socket directory %s is not private to this user", filepath.Dir(path))
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s is not a socket", path)
+	}
+	if !ownedByUser(info) {
+		return fmt.Errorf("socket %s is owned by another user", path)
+	}
+	return nil
+}
+
+func ownedByUser(info os.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid()
+}
+
+// removeStaleSockets deletes sockets left in dir by instances that are no
+// longer running, such as after a crash
+func removeStaleSockets(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "mpv-") || !strings.HasSuffix(name, ".sock") {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "mpv-"), ".sock"))
+		if err != nil || pid == os.Getpid() || processAlive(pid) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err == nil {
+			log.Printf("Player: Removed stale socket %s", name)
+		}
+	}
+}
+
+// processAlive reports whether a process with the given pid exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,90 @@
+package player
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSocketDir(t *testing.T) {
+	runtimeDir := privateTempDir(t)
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	dir, err := socketDir()
+	if err != nil {
+		t.Fatalf("socketDir failed: %v", err)
+	}
+	if dir != filepath.Join(runtimeDir, "podcast-tui") {
+		t.Errorf("Expected the socket in the runtime dir, got %s", dir)
+	}
+	if info, _ := os.Stat(dir); info.Mode().Perm() != 0700 {
+		t.Errorf("Expected mode 0700, got %v", info.Mode().Perm())
+	}
+
+	// Without a runtime dir, a private directory in the cache dir is used,
+	// tightening one that was left open
+	cacheDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv("XDG_CACHE_HOME", cacheDir)
+	if err := os.MkdirAll(filepath.Join(cacheDir, "podcast-tui"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dir, err = socketDir()
+	if err != nil {
+		t.Fatalf("socketDir failed: %v", err)
+	}
+	if dir != filepath.Join(cacheDir, "podcast-tui") {
+		t.Errorf("Expected the socket in the cache dir, got %s", dir)
+	}
+	if info, _ := os.Stat(dir); info.Mode().Perm() != 0700 {
+		t.Errorf("Expected mode 0700, got %v", info.Mode().Perm())
+	}
+}
+
+func TestCheckSocket(t *testing.T) {
+	dir := privateTempDir(t)
+	path := socketPath(dir, os.Getpid())
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	if err := checkSocket(path); err != nil {
+		t.Errorf("Expected our socket to be trusted, got %v", err)
+	}
+
+	file := filepath.Join(dir, "mpv-1.sock")
+	os.WriteFile(file, nil, 0600)
+	if err := checkSocket(file); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("Expected a regular file to be refused, got %v", err)
+	}
+
+	os.Chmod(dir, 0755)
+	if err := checkSocket(path); err == nil {
+		t.Error("Expected a socket in a shared directory to be refused")
+	}
+}
+
+func TestRemoveStaleSockets(t *testing.T) {
+	dir := privateTempDir(t)
+	// Past the largest pid Linux allows, so never running
+	stale := socketPath(dir, 1<<23)
+	own := socketPath(dir, os.Getpid())
+	other := filepath.Join(dir, "notes.txt")
+	for _, path := range []string{stale, own, other} {
+		os.WriteFile(path, nil, 0600)
+	}
+
+	removeStaleSockets(dir)
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("Expected the socket of a dead process to be removed")
+	}
+	for _, path := range []string{own, other} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be kept", filepath.Base(path))
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package privacy removes analytics redirects from episode URLs and defines the
+// User-Agent the app identifies itself with
+package privacy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultUserAgent identifies the app honestly to feed, download and API servers
+const DefaultUserAgent = "podcast-tui/1.0 (+https://github.com/csams/podcast-tui)"
+
+// RulesFile is the name of the file in the config directory holding the user's
+// own tracking prefixes, one pattern per line
+const RulesFile = "tracking-prefixes.txt"
+
+// maxUnwraps bounds how many prefixes are removed from one URL
+const maxUnwraps = 10
+
+// builtinPatterns are the prefixes of well-known podcast analytics services, which
+// record a download and redirect to the URL that follows the prefix
+var builtinPatterns = []string{
+	"dts.podtrac.com/redirect.*/",
+	"www.podtrac.com/pts/redirect.*/",
+	"podtrac.com/pts/redirect.*/",
+	"chrt.fm/track/*/",
+	"chtbl.com/track/*/",
+	"pdst.fm/e/",
+	"op3.dev/e/",
+	"op3.dev/e,*/",
+	"pfx.vpixl.com/*/",
+	"mgln.ai/e/*/",
+	"arttrk.com/p/*/",
+	"verifi.podscribe.com/rss/p/",
+	"pscrb.fm/rss/p/",
+	"prfx.byspotify.com/e/",
+	"claritaspod.com/measure/",
+	"tracking.swap.fm/track/*/",
+	"growx.podkite.com/*/",
+}
+
+// Rule matches one tracking prefix. Patterns are a host and path without the
+// scheme, such as "chrt.fm/track/*/", where "*" matches within one path segment.
+type Rule struct {
+	Pattern  string
+	segments []string
+}
+
+// ParseRule parses a prefix pattern
+func ParseRule(pattern string) (Rule, error) {
+	pattern = strings.TrimSpace(pattern)
+	pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "https://"), "http://")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return Rule{}, errors.New("empty pattern")
+	}
+
+	segments := strings.Split(pattern, "/")
+	if !strings.Contains(segments[0], ".") {
+		return Rule{}, fmt.Errorf("pattern %q must start with a host", pattern)
+	}
+	for i, segment := range segments {
+		if segment == "" {
+			return Rule{}, fmt.Errorf("pattern %q has an empty path segment", pattern)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return Rule{}, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		if i == 0 {
+			segments[i] = strings.ToLower(segment)
+		}
+	}
+	return Rule{Pattern: pattern + "/", segments: segments}, nil
+}
+
+// strip removes the prefix from rest, a URL without its scheme, returning what follows
+func (r Rule) strip(rest string) (string, bool) {
+	parts := strings.SplitN(rest, "/", len(r.segments)+1)
+	if len(parts) <= len(r.segments) {
+		return "", false
+	}
+	for i, segment := range r.segments {
+		part := parts[i]
+		if i == 0 {
+			part = strings.ToLower(part)
+		}
+		if ok, _ := path.Match(segment, part); !ok {
+			return "", false
+		}
+	}
+	return parts[len(r.segments)], true
+}
+
+// Unwrapper removes tracking prefixes from URLs
+type Unwrapper struct {
+	rules []Rule
+}
+
+// NewUnwrapper returns an unwrapper for the built-in prefixes and any extra rules
+func NewUnwrapper(extra ...Rule) *Unwrapper {
+	u := &Unwrapper{}
+	for _, pattern := range builtinPatterns {
+		rule, err := ParseRule(pattern)
+		if err != nil {
+			panic(err)
+		}
+		u.rules = append(u.rules, rule)
+	}
+	u.rules = append(u.rules, extra...)
+	return u
+}
+
+// schemePattern matches the scheme of an embedded URL, whose slashes some
+// prefixes collapse ("https:/host/...")
+var schemePattern = regexp.MustCompile(`^(?i)(https?):/+`)
+
+// Unwrap returns rawURL with any chain of tracking prefixes removed. URLs
+// without a known prefix are returned unchanged.
+func (u *Unwrapper) Unwrap(rawURL string) string {
+	current := strings.TrimSpace(rawURL)
+	for i := 0; i < maxUnwraps; i++ {
+		next, ok := u.unwrapOnce(current)
+		if !ok {
+			break
+		}
+		current = next
+	}
+	return current
+}
+
+func (u *Unwrapper) unwrapOnce(rawURL string) (string, bool) {
+	m := schemePattern.FindStringSubmatch(rawURL)
+	if m == nil {
+		return "", false
+	}
+	scheme, rest := strings.ToLower(m[1]), rawURL[len(m[0]):]
+
+	for _, rule := range u.rules {
+		inner, ok := rule.strip(rest)
+		if !ok {
+			continue
+		}
+		// Some prefixes carry the target URL escaped
+		if lower := strings.ToLower(inner); strings.HasPrefix(lower, "http%3a") || strings.HasPrefix(lower, "https%3a") {
+			if unescaped, err := url.PathUnescape(inner); err == nil {
+				inner = unescaped
+			}
+		}
+		if m := schemePattern.FindStringSubmatch(inner); m != nil {
+			return strings.ToLower(m[1]) + "://" + inner[len(m[0]):], true
+		}
+		// Otherwise the target is a host and path, and keeps the outer scheme
+		host, _, hasPath := strings.Cut(inner, "/")
+		if !hasPath || !strings.Contains(host, ".") || strings.ContainsAny(host, "?#") {
+			continue
+		}
+		return scheme + "://" + inner, true
+	}
+	return "", false
+}
+
+// LoadRules reads the user's tracking prefixes from RulesFile in configDir. Blank
+// lines and lines starting with "#" are ignored. A missing file yields no rules;
+// invalid lines are skipped and reported in the returned error.
+func LoadRules(configDir string) ([]Rule, error) {
+	f, err := os.Open(filepath.Join(configDir, RulesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []Rule
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		rule, err := ParseRule(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s line %d: %w", RulesFile, line, err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return rules, errors.Join(errs...)
+}
@@ -0,0 +1,117 @@
+package privacy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnwrap(t *testing.T) {
+	u := NewUnwrapper()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "podtrac",
+			input: "https://dts.podtrac.com/redirect.mp3/traffic.megaphone.fm/ABC123.mp3?updated=1700000000",
+			want:  "https://traffic.megaphone.fm/ABC123.mp3?updated=1700000000",
+		},
+		{
+			name:  "chain of prefixes",
+			input: "https://pdst.fm/e/chrt.fm/track/9EE2G/pfx.vpixl.com/6qj4J/DTS.PODTRAC.COM/redirect.mp3/mgln.ai/e/103/media.example.com/ep.mp3",
+			want:  "https://media.example.com/ep.mp3",
+		},
+		{
+			name:  "embedded URL keeps its own scheme",
+			input: "https://op3.dev/e/http://media.example.com/ep.mp3",
+			want:  "http://media.example.com/ep.mp3",
+		},
+		{
+			name:  "collapsed slashes",
+			input: "https://op3.dev/e,pg=abc/https:/media.example.com/ep.mp3",
+			want:  "https://media.example.com/ep.mp3",
+		},
+		{
+			name:  "escaped URL",
+			input: "https://claritaspod.com/measure/https%3A%2F%2Fmedia.example.com%2Fep.mp3",
+			want:  "https://media.example.com/ep.mp3",
+		},
+		{
+			name:  "outer scheme kept",
+			input: "http://chtbl.com/track/1234/media.example.com/ep.mp3",
+			want:  "http://media.example.com/ep.mp3",
+		},
+		{
+			name:  "no prefix",
+			input: "https://media.example.com/chrt.fm/track/1/ep.mp3",
+			want:  "https://media.example.com/chrt.fm/track/1/ep.mp3",
+		},
+		{
+			name:  "prefix without a target host",
+			input: "https://chrt.fm/track/1234/ep.mp3",
+			want:  "https://chrt.fm/track/1234/ep.mp3",
+		},
+		{
+			name:  "prefix only",
+			input: "https://pdst.fm/e/",
+			want:  "https://pdst.fm/e/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := u.Unwrap(tt.input); got != tt.want {
+				t.Errorf("Unwrap(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	for _, pattern := range []string{"https://stats.example.net/hit/*/", "stats.example.net/r", "*.tracker.example/e/*/"} {
+		if _, err := ParseRule(pattern); err != nil {
+			t.Errorf("ParseRule(%q) failed: %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "track/*/", "stats.example.net//x/", "stats.example.net/[/"} {
+		if _, err := ParseRule(pattern); err == nil {
+			t.Errorf("Expected ParseRule(%q) to fail", pattern)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+
+	rules, err := LoadRules(dir)
+	if err != nil || rules != nil {
+		t.Fatalf("Expected no rules without a file, got %v, %v", rules, err)
+	}
+
+	content := `# My tracking prefixes
+stats.example.net/hit/*/
+
+not-a-host/
+https://*.tracker.example/e/
+`
+	if err := os.WriteFile(filepath.Join(dir, RulesFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err = LoadRules(dir)
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("Expected an error for line 4, got %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected the 2 valid rules, got %d", len(rules))
+	}
+
+	u := NewUnwrapper(rules...)
+	got := u.Unwrap("https://stats.example.net/hit/42/eu.tracker.example/e/cdn.example.org/ep.mp3")
+	if got != "https://cdn.example.org/ep.mp3" {
+		t.Errorf("Expected user rules to be applied, got %q", got)
+	}
+}
@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -13,10 +15,12 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/csams/podcast-tui/internal/credentials"
 	"github.com/csams/podcast-tui/internal/download"
 	"github.com/csams/podcast-tui/internal/feed"
 	"github.com/csams/podcast-tui/internal/models"
 	"github.com/csams/podcast-tui/internal/player"
+	"github.com/csams/podcast-tui/internal/privacy"
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -29,7 +33,7 @@ type App struct {
 	podcasts        *PodcastListView
 	episodes        *EpisodeListView
 	queue           *QueueView
-	player          *player.Player
+	player          player.Backend
 	downloadManager *download.Manager
 	subscriptions   *models.Subscriptions
 	commandLine     string
@@ -38,21 +42,40 @@ type App struct {
 	currentPodcast  *models.Podcast
 	helpDialog      *HelpDialog
 	confirmDialog   *ConfirmationDialog
+	healthDialog    *FeedHealthDialog
+	directoryView   *DirectoryView
+	feedPicker      *FeedPickerDialog
 	configDir       string
 	settings        *Settings
+	credentials     *credentials.Store
+	trackers        *privacy.Unwrapper
 	shutdownOnce    sync.Once
 	positionTicker  *time.Ticker
 	positionUpdate  chan struct{}
+	sleep           sleepTimer
 	
 	// Refresh management
 	refreshSemaphore chan struct{}
 	activeRefreshes  map[string]bool // Track which podcasts are currently refreshing
 	refreshMutex     sync.Mutex      // Protect activeRefreshes map
+	hostNextFetch    map[string]time.Time // Earliest time the next request may hit each host
+
+	// New episodes found by the most recent refreshes, for jumping to them with 'N'
+	newEpisodes      []*models.Episode
+	newEpisodeCursor int
 	
+	// Playback work started in the background, see background
+	tasks sync.WaitGroup
+
 	// Episode transition management
 	transitionMutex     sync.Mutex    // Protect episode transitions
 	transitionInProgress bool         // Flag to indicate transition is happening
 	completionHandled   atomic.Bool   // Atomic flag to prevent double completion handling
+
+	// The episode the player starts when the current one ends, see preloadNext
+	preloadMu    sync.Mutex
+	preloaded    *models.Episode
+	preloadedURL string
 }
 
 type Mode int
@@ -69,6 +92,11 @@ type View interface {
 }
 
 func NewApp() *App {
+	return newApp(player.New())
+}
+
+// newApp creates the app with the given playback backend
+func newApp(backend player.Backend) *App {
 	// Get config directory
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -80,13 +108,16 @@ func NewApp() *App {
 	app := &App{
 		quit:             make(chan struct{}),
 		mode:             ModeNormal,
-		player:           player.New(),
+		player:           backend,
 		helpDialog:       NewHelpDialog(),
 		confirmDialog:    NewConfirmationDialog(),
+		healthDialog:     NewFeedHealthDialog(),
+		feedPicker:       NewFeedPickerDialog(),
 		configDir:        configDir,
 		positionUpdate:   make(chan struct{}, 1),
 		refreshSemaphore: make(chan struct{}, 10), // Allow up to 10 concurrent refreshes
 		activeRefreshes:  make(map[string]bool),
+		hostNextFetch:    make(map[string]time.Time),
 	}
 
 	// Initialize download manager
@@ -99,6 +130,24 @@ func NewApp() *App {
 		settings = DefaultSettings()
 	}
 	app.settings = settings
+	backend.SetSmartRewind(settings.Rewind())
+
+	// Load credentials for private feeds
+	creds, err := credentials.Load(configDir)
+	if err != nil {
+		log.Printf("Failed to load credentials: %v", err)
+	}
+	app.credentials = creds
+	app.downloadManager.SetCredentialLookup(app.enclosureCredential)
+
+	// Tracking prefixes removed in privacy mode, including the user's own
+	rules, err := privacy.LoadRules(configDir)
+	if err != nil {
+		log.Printf("Failed to load tracking prefixes: %v", err)
+	}
+	app.trackers = privacy.NewUnwrapper(rules...)
+	app.downloadManager.SetUserAgent(settings.HTTPUserAgent())
+	app.downloadManager.SetURLRewriter(app.untrackedURL)
 
 	return app
 }
@@ -136,8 +185,41 @@ func (a *App) Run() error {
 		}
 	}()
 
-	s.SetStyle(tcell.StyleDefault.Background(ColorBg).Foreground(ColorFg))
-	s.Clear()
+	a.setup()
+
+	// Set up signal handling for graceful shutdown
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Println("Received interrupt signal, shutting down...")
+		// Post an interrupt event to ensure event loop exits
+		if a.screen != nil {
+			a.screen.PostEvent(tcell.NewEventInterrupt(nil))
+		}
+		close(a.quit)
+	}()
+
+	go a.handleEvents()
+	go a.handleProgress()
+	go a.handlePlayerEvents()
+	go a.handleDownloadProgress()
+	go a.runRefreshScheduler()
+	a.draw()
+
+	<-a.quit
+
+	// Cleanup has already been initiated by quit handler
+	log.Println("Shutdown complete")
+
+	return nil
+}
+
+// setup loads the subscriptions, starts the download manager and player, and
+// creates the views on the initialized screen
+func (a *App) setup() {
+	a.screen.SetStyle(tcell.StyleDefault.Background(ColorBg).Foreground(ColorFg))
+	a.screen.Clear()
 
 	// Load subscriptions
 	subs, err := models.LoadSubscriptions()
@@ -168,33 +250,10 @@ func (a *App) Run() error {
 	a.queue.SetSubscriptions(subs)
 	a.queue.SetDownloadManager(a.downloadManager)
 	a.queue.SetPlayer(a.player)
+	a.directoryView = NewDirectoryView()
+	a.directoryView.SetSubscriptions(subs)
 	a.currentView = a.podcasts
 	a.previousView = a.podcasts
-
-	// Set up signal handling for graceful shutdown
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		log.Println("Received interrupt signal, shutting down...")
-		// Post an interrupt event to ensure event loop exits
-		if a.screen != nil {
-			a.screen.PostEvent(tcell.NewEventInterrupt(nil))
-		}
-		close(a.quit)
-	}()
-
-	go a.handleEvents()
-	go a.handleProgress()
-	go a.handleDownloadProgress()
-	a.draw()
-
-	<-a.quit
-
-	// Cleanup has already been initiated by quit handler
-	log.Println("Shutdown complete")
-
-	return nil
 }
 
 // shutdown performs all cleanup operations
@@ -250,7 +309,7 @@ func (a *App) handleEvents() {
 			a.updateCurrentPosition()
 			
 			// If a modal is visible, we need to do a full redraw to keep it on screen
-			if a.helpDialog.IsVisible() || a.confirmDialog.IsVisible() {
+			if a.isModalVisible() {
 				a.draw()
 			} else {
 				// Update the position column in current view
@@ -296,6 +355,16 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 		return a.confirmDialog.HandleKey(ev)
 	}
 
+	// Feed health dialog takes precedence over normal input
+	if a.healthDialog.IsVisible() {
+		return a.healthDialog.HandleKey(ev)
+	}
+
+	// Feed picker takes precedence over normal input
+	if a.feedPicker.IsVisible() {
+		return a.feedPicker.HandleKey(ev)
+	}
+
 	if a.mode == ModeNormal {
 		switch ev.Key() {
 		case tcell.KeyRune:
@@ -308,6 +377,7 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 					// Get the episode being moved before the operation
 					selectedEpisode := a.queue.GetSelected()
 					if selectedEpisode != nil && a.subscriptions.MoveQueueItemDown(selectedIdx) {
+						a.preloadNext()
 						if err := a.subscriptions.Save(); err != nil {
 							a.statusMessage = "Error saving queue: " + err.Error()
 						} else {
@@ -339,6 +409,7 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 					// Get the episode being moved before the operation
 					selectedEpisode := a.queue.GetSelected()
 					if selectedEpisode != nil && a.subscriptions.MoveQueueItemUp(selectedIdx) {
+						a.preloadNext()
 						if err := a.subscriptions.Save(); err != nil {
 							a.statusMessage = "Error saving queue: " + err.Error()
 						} else {
@@ -400,6 +471,10 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 					a.clearStatusMessage()
 					a.currentView = a.podcasts
 					return true
+				} else if a.currentView == a.directoryView {
+					a.clearStatusMessage()
+					a.currentView = a.previousView
+					return true
 				}
 			case 'l':
 				if a.currentView == a.podcasts {
@@ -676,16 +751,26 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 						go a.refreshSinglePodcast(podcast)
 					}
 				} else {
-					// In podcast list view, refresh all feeds
-					totalPodcasts := len(a.subscriptions.Podcasts)
-					if totalPodcasts == 0 {
-						a.statusMessage = "No podcasts to refresh"
-					} else {
-						a.statusMessage = fmt.Sprintf("Starting refresh of %d podcasts...", totalPodcasts)
-						go a.refreshFeeds()
-					}
+					// In podcast list view, refresh feeds that are due
+					a.startDueRefresh()
 				}
 				return true
+			case 'z':
+				// Start the sleep timer, or add 15 minutes to it
+				a.extendSleepTimer()
+				return true
+			case 'Z':
+				// Cancel the sleep timer
+				a.cancelSleepTimer()
+				return true
+			case 'H':
+				// Show feed health for the selected podcast
+				a.showFeedHealth()
+				return true
+			case 'N':
+				// Jump to the next episode found by the latest refreshes
+				a.jumpToNextNewEpisode()
+				return true
 			case 's':
 				// Stop playback
 				if a.player.GetState() != player.StateStopped {
@@ -816,9 +901,17 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 					go a.playEpisode(episode)
 					return true
 				}
+			} else if a.currentView == a.directoryView {
+				a.subscribeFromDirectory()
+				return true
 			}
 		case tcell.KeyEscape:
 			a.mode = ModeNormal
+			// Leave directory results
+			if a.currentView == a.directoryView {
+				a.clearStatusMessage()
+				a.currentView = a.previousView
+			}
 			return true
 		case tcell.KeyTab:
 			// TAB switches to queue view from podcast/episode view, or returns to previous view from queue
@@ -903,6 +996,8 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 				return a.podcasts.HandlePageDown()
 			} else if a.currentView == a.episodes {
 				return a.episodes.HandlePageDown()
+			} else if a.currentView == a.directoryView {
+				return a.directoryView.HandlePageDown()
 			}
 			return false
 		case tcell.KeyCtrlB:
@@ -913,6 +1008,8 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 				return a.podcasts.HandlePageUp()
 			} else if a.currentView == a.episodes {
 				return a.episodes.HandlePageUp()
+			} else if a.currentView == a.directoryView {
+				return a.directoryView.HandlePageUp()
 			}
 			return false
 		}
@@ -1043,6 +1140,12 @@ func (a *App) handleKey(ev *tcell.EventKey) bool {
 	return false
 }
 
+// isModalVisible reports whether a dialog is drawn over the current view
+func (a *App) isModalVisible() bool {
+	return a.helpDialog.IsVisible() || a.confirmDialog.IsVisible() || a.healthDialog.IsVisible() ||
+		a.feedPicker.IsVisible()
+}
+
 func (a *App) draw() {
 	// Try using Fill instead of Clear to force all cells to update
 	w, h := a.screen.Size()
@@ -1059,6 +1162,12 @@ func (a *App) draw() {
 	// Draw help dialog on top of everything if visible
 	a.helpDialog.Draw(a.screen)
 
+	// Draw feed health dialog on top of everything if visible
+	a.healthDialog.Draw(a.screen)
+
+	// Draw feed picker on top of everything if visible
+	a.feedPicker.Draw(a.screen)
+
 	// Draw confirmation dialog on top of everything if visible
 	a.confirmDialog.Draw(a.screen)
 
@@ -1067,46 +1176,18 @@ func (a *App) draw() {
 
 func (a *App) handleProgress() {
 	saveCounter := 0
-	var lastEpisodeID string
-	var lastPlayerState player.PlayerState = player.StateStopped
 
 	for progress := range a.player.Progress() {
+		a.tickSleepTimer()
+
 		// Don't redraw the entire screen - just update the status bar
 		// The position ticker handles updating the episode list view
 		// But if a modal is visible, skip partial updates to avoid overwriting it
-		if !a.helpDialog.IsVisible() && !a.confirmDialog.IsVisible() {
+		if !a.isModalVisible() {
 			a.drawStatusBar()
 			a.screen.Show()
 		}
 
-		// Get current player state
-		currentPlayerState := a.player.GetState()
-
-		// Reset completion flag when episode changes
-		if a.currentEpisode != nil && a.currentEpisode.ID != lastEpisodeID {
-			lastEpisodeID = a.currentEpisode.ID
-			a.completionHandled.Store(false)
-			lastPlayerState = currentPlayerState
-		}
-
-		// Check if player just stopped (state changed from playing/paused to stopped)
-		if a.currentEpisode != nil && 
-			(lastPlayerState == player.StatePlaying || lastPlayerState == player.StatePaused) &&
-			currentPlayerState == player.StateStopped {
-			// Player just stopped - check if we're at the end
-			if progress.Duration > 0 && progress.Position >= progress.Duration {
-				// Try to set the completion flag atomically
-				if a.completionHandled.CompareAndSwap(false, true) {
-					log.Printf("Episode completion detected via state change! Position: %v, Duration: %v",
-						progress.Position, progress.Duration)
-					
-					// Handle completion in a goroutine to avoid blocking progress updates
-					go a.handleEpisodeCompletion()
-				}
-			}
-		}
-		lastPlayerState = currentPlayerState
-
 		// Log progress for debugging when near the end
 		if progress.Duration > 0 {
 			percentComplete := float64(progress.Position) / float64(progress.Duration) * 100
@@ -1127,6 +1208,52 @@ func (a *App) handleProgress() {
 	}
 }
 
+// handlePlayerEvents reacts to events from the player, such as an episode
+// playing to its end
+func (a *App) handlePlayerEvents() {
+	for event := range a.player.Events() {
+		a.handlePlayerEvent(event)
+	}
+}
+
+// handlePlayerEvent reacts to one event from the player
+func (a *App) handlePlayerEvent(event player.Event) {
+	switch event.Type {
+	case player.EventEndOfFile:
+		if a.currentEpisode == nil {
+			return
+		}
+		// Try to set the completion flag atomically
+		if a.completionHandled.CompareAndSwap(false, true) {
+			log.Printf("Episode completed: %s", a.currentEpisode.Title)
+			// Handle completion in a goroutine to avoid blocking player events
+			a.background(a.handleEpisodeCompletion)
+		}
+	case player.EventError:
+		a.background(func() { a.handlePlaybackError(event.Err) })
+	case player.EventNextFile:
+		// Handled in order, before the end of the file that took over
+		a.handleNextFile(event.URL)
+	case player.EventRecovered:
+		// mpv crashed and the backend restarted it where it was, without
+		// the preloaded episode
+		position, _ := a.player.GetPosition()
+		a.statusMessage = fmt.Sprintf("Restarted the player: %v; resumed at %s", event.Err, a.formatTime(position))
+		a.draw()
+		a.background(a.preloadNext)
+	}
+}
+
+// background runs f in its own goroutine, tracked in tasks so tests can wait
+// for the playback work an event started
+func (a *App) background(f func()) {
+	a.tasks.Add(1)
+	go func() {
+		defer a.tasks.Done()
+		f()
+	}()
+}
+
 func (a *App) formatTime(d time.Duration) string {
 	totalSeconds := int(d.Seconds())
 	hours := totalSeconds / 3600
@@ -1186,6 +1313,21 @@ func (a *App) formatPlayerStatus(width int) string {
 		a.formatTime(duration))
 	statusParts = append(statusParts, progressStr)
 
+	// The sleep timer's countdown matters at any width
+	if countdown := a.sleepCountdown(position, duration); countdown != "" {
+		statusParts = append(statusParts, countdown)
+	}
+
+	// So do audio filters, which change what is heard; narrow bars only note that
+	// some are on
+	if filters := a.player.GetAudioFilters().Names(); len(filters) > 0 {
+		if width > 120 {
+			statusParts = append(statusParts, fmt.Sprintf("[AF:%s]", strings.Join(filters, "+")))
+		} else {
+			statusParts = append(statusParts, "[AF]")
+		}
+	}
+
 	// If we have enough width, add speed and volume
 	if width > 120 {
 		speed, _ := a.player.GetSpeed()
@@ -1324,7 +1466,36 @@ func (a *App) executeCommand() {
 			a.statusMessage = "Usage: add <feed-url>"
 			return
 		}
-		go a.addPodcast(parts[1])
+		// Take the rest of the line as typed, since folder paths may contain spaces
+		go a.addPodcast(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(a.commandLine), "add")))
+	case "auth":
+		a.setFeedAuth(parts[1:])
+	case "directory", "dir":
+		a.searchDirectory(strings.Join(parts[1:], " "))
+	case "history":
+		a.startHistoryFetch()
+	case "media":
+		a.setMediaPreference(parts[1:])
+	case "sleep":
+		a.setSleepTimer(parts[1:])
+	case "filter", "af":
+		a.setAudioFilter(parts[1:])
+	case "skip":
+		a.setSkip(parts[1:])
+	case "header":
+		a.setFeedHeader(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(a.commandLine), "header")))
+	case "refresh":
+		// Refresh due feeds, or every feed with "refresh all"
+		if len(parts) > 1 && parts[1] == "all" {
+			if len(a.subscriptions.Podcasts) == 0 {
+				a.statusMessage = "No podcasts to refresh"
+				return
+			}
+			a.statusMessage = fmt.Sprintf("Starting refresh of %d podcasts...", len(a.subscriptions.Podcasts))
+			go a.refreshFeeds()
+		} else {
+			a.startDueRefresh()
+		}
 	case "Q", "quit":
 		// Post an interrupt event to ensure event loop exits
 		if a.screen != nil {
@@ -1348,22 +1519,52 @@ func (a *App) addPodcast(url string) {
 	a.statusMessage = "Adding podcast..."
 	a.draw() // Show status immediately
 
-	podcast, err := feed.ParseFeed(url)
+	// Credentials given in the URL are stored separately, never in subscriptions.json
+	url, cred := credentials.SplitUserInfo(feed.Resolve(url))
+	if cred == nil {
+		cred = a.credentials.Get(url)
+	}
+	a.subscribe(url, cred, true)
+}
+
+// subscribe fetches the feed at url and adds it to the subscriptions. When url is a
+// web page, the feeds it advertises are offered instead if discover is set.
+func (a *App) subscribe(url string, cred *credentials.Credential, discover bool) {
+	url = models.NormalizeFeedURL(url)
+
+	// Paginated feeds are fetched in full so the whole back catalog is available
+	result, err := feed.FetchHistory(url, feed.Options{Credential: cred, MaxBytes: a.settings.MaxFeedBytes(), UserAgent: a.settings.HTTPUserAgent()}, a.settings.HistoryLimits())
+	var notFeed *feed.NotFeedError
+	if errors.As(err, &notFeed) && discover && len(notFeed.Feeds) > 0 {
+		a.subscribeDiscovered(url, cred, notFeed.Feeds)
+		return
+	}
 	if err != nil {
 		a.statusMessage = "Error: " + err.Error()
-		log.Printf("Failed to add podcast from %s: %v", url, err)
+		log.Printf("Failed to add podcast from %s: %v", credentials.RedactURL(url), err)
 		a.draw() // Show error
 		return
 	}
+	podcast := result.Podcast
+
+	// Subscribe at the feed's permanent location
+	if newURL := result.NewLocation(); newURL != "" {
+		podcast.MoveTo(newURL)
+	}
 
-	// Check if already subscribed
-	for _, p := range a.subscriptions.Podcasts {
-		if p.URL == url {
-			a.statusMessage = "Already subscribed to: " + p.Title
+	// Check if already subscribed, including under an equivalent URL or one the
+	// feed has moved from, and offer to merge the same feed reached another way
+	if existing, reason := a.subscriptions.FindDuplicate(podcast); existing != nil {
+		if existing.HasURL(url) || existing.HasURL(podcast.URL) {
+			a.statusMessage = "Already subscribed to: " + existing.Title
 			a.draw()
 			return
 		}
+		log.Printf("Feed %s duplicates '%s': %s", credentials.RedactURL(url), existing.Title, reason)
+		a.offerMerge(existing, podcast, reason, cred)
+		return
 	}
+	podcast.RecordRefreshSuccess(time.Now(), http.StatusOK)
 
 	a.subscriptions.Add(podcast)
 	if err := a.subscriptions.Save(); err != nil {
@@ -1373,16 +1574,74 @@ func (a *App) addPodcast(url string) {
 		return
 	}
 
+	if cred != nil {
+		a.credentials.Set(url, cred)
+		a.credentials.Move(url, podcast.URL)
+		if err := a.credentials.Save(); err != nil {
+			log.Printf("Failed to save credentials: %v", err)
+		}
+	}
+
 	a.podcasts.SetSubscriptions(a.subscriptions)
+	a.directoryView.SetSubscriptions(a.subscriptions)
 	a.statusMessage = "Added: " + podcast.Title + fmt.Sprintf(" (%d episodes)", len(podcast.Episodes))
+	if result.Pages > 1 {
+		a.statusMessage += fmt.Sprintf(" from %d pages", result.Pages)
+	}
+	if result.NextPage != "" {
+		a.statusMessage += "; older episodes not fetched (:history for more)"
+	}
 	a.draw() // Update UI to show new podcast
 }
 
+// subscribeDiscovered subscribes to the feed a web page advertises, or lets the user
+// pick one when it advertises several
+func (a *App) subscribeDiscovered(pageURL string, cred *credentials.Credential, feeds []feed.DiscoveredFeed) {
+	// Credentials given for the page only go to feeds on the same host
+	credFor := func(feedURL string) *credentials.Credential {
+		if stored := a.credentials.Get(feedURL); stored != nil {
+			return stored
+		}
+		if cred != nil && credentials.SameHost(pageURL, feedURL) {
+			return cred
+		}
+		return nil
+	}
+
+	if len(feeds) == 1 {
+		log.Printf("Found feed %s on %s", credentials.RedactURL(feeds[0].URL), credentials.RedactURL(pageURL))
+		a.subscribe(feeds[0].URL, credFor(feeds[0].URL), false)
+		return
+	}
+
+	a.statusMessage = fmt.Sprintf("Found %d feeds; choose one to subscribe", len(feeds))
+	a.feedPicker.Show(credentials.RedactURL(pageURL), feeds, func(chosen feed.DiscoveredFeed) {
+		a.statusMessage = "Adding podcast..."
+		go a.subscribe(chosen.URL, credFor(chosen.URL), false)
+	})
+	a.draw()
+}
+
 func (a *App) refreshFeeds() {
-	totalPodcasts := len(a.subscriptions.Podcasts)
+	// Retired feeds are gone for good; only a single-podcast refresh retries them
+	var active []*models.Podcast
+	for _, podcast := range a.subscriptions.Podcasts {
+		if !podcast.Retired {
+			active = append(active, podcast)
+		}
+	}
+	a.refreshPodcasts(active, false)
+}
+
+// refreshPodcasts refreshes the given podcasts concurrently. Background refreshes
+// don't report progress in the status bar and only announce new episodes.
+func (a *App) refreshPodcasts(podcasts []*models.Podcast, background bool) {
+	totalPodcasts := len(podcasts)
 
 	if totalPodcasts == 0 {
-		a.statusMessage = "No podcasts to refresh"
+		if !background {
+			a.statusMessage = "No podcasts to refresh"
+		}
 		return
 	}
 
@@ -1391,11 +1650,16 @@ func (a *App) refreshFeeds() {
 	successCount := int32(0)
 	failedCount := int32(0)
 	processedCount := int32(0)
-	
+
+	// Collect episodes that weren't known before this refresh
+	var newMutex sync.Mutex
+	var newEpisodes []*models.Episode
+	podcastsWithNew := 0
+
 	startTime := time.Now()
-	
+
 	// Start concurrent refreshes for all podcasts
-	for _, podcast := range a.subscriptions.Podcasts {
+	for _, podcast := range podcasts {
 		// Skip if already refreshing
 		a.refreshMutex.Lock()
 		if a.activeRefreshes[podcast.URL] {
@@ -1405,64 +1669,95 @@ func (a *App) refreshFeeds() {
 		}
 		a.activeRefreshes[podcast.URL] = true
 		a.refreshMutex.Unlock()
-		
+
 		wg.Add(1)
-		go func(p *models.Podcast) {
+		go func(p *models.Podcast, feedURL string) {
 			defer wg.Done()
-			
-			// Acquire semaphore slot
-			a.refreshSemaphore <- struct{}{}
 			defer func() {
-				<-a.refreshSemaphore
 				a.refreshMutex.Lock()
-				delete(a.activeRefreshes, p.URL)
+				delete(a.activeRefreshes, feedURL)
 				a.refreshMutex.Unlock()
 			}()
-			
+
+			// Don't hammer hosts that serve several of our feeds. Waiting
+			// before taking a semaphore slot leaves the slots to feeds on
+			// other hosts meanwhile.
+			a.waitForHost(p.URL)
+
+			// Acquire semaphore slot
+			a.refreshSemaphore <- struct{}{}
+			defer func() { <-a.refreshSemaphore }()
+
 			// Update progress
 			current := atomic.AddInt32(&processedCount, 1)
-			percentage := (current * 100) / int32(totalPodcasts)
-			a.statusMessage = fmt.Sprintf("Refreshing feeds... %d%% (%d/%d)",
-				percentage, current, totalPodcasts)
-			a.draw()
-			
+			if !background {
+				percentage := (current * 100) / int32(totalPodcasts)
+				a.statusMessage = fmt.Sprintf("Refreshing feeds... %d%% (%d/%d)",
+					percentage, current, totalPodcasts)
+				a.draw()
+			}
+
 			// Parse the feed
-			updated, err := feed.ParseFeed(p.URL)
+			result, err := feed.Refresh(p, a.feedOptions(p, feedURL))
 			if err != nil {
-				log.Printf("Failed to refresh podcast '%s' from %s: %v", p.Title, p.URL, err)
+				log.Printf("Failed to refresh podcast '%s' from %s: %v", p.Title, credentials.RedactURL(feedURL), err)
+				a.recordFeedFailure(p, err)
 				atomic.AddInt32(&failedCount, 1)
 				return
 			}
-			
+			if result.NotModified {
+				p.RecordRefreshSuccess(time.Now(), http.StatusNotModified)
+				atomic.AddInt32(&successCount, 1)
+				return
+			}
+
 			// Merge the updated data
-			a.mergePodcastData(p, updated)
+			added := a.mergePodcastData(p, result.Podcast)
+			p.RecordRefreshSuccess(time.Now(), http.StatusOK)
+			a.applyFeedMove(p, result)
 			atomic.AddInt32(&successCount, 1)
-		}(podcast)
+
+			if len(added) > 0 {
+				newMutex.Lock()
+				newEpisodes = append(newEpisodes, added...)
+				podcastsWithNew++
+				newMutex.Unlock()
+			}
+		}(podcast, podcast.URL)
 	}
-	
+
 	// Wait for all refreshes to complete
 	wg.Wait()
-	
+
 	// Log refresh summary
 	failed := atomic.LoadInt32(&failedCount)
 	success := atomic.LoadInt32(&successCount)
-	log.Printf("Feed refresh completed: %d successful, %d failed out of %d total", 
-		success, failed, totalPodcasts)
-	
+	log.Printf("Feed refresh completed: %d successful, %d failed out of %d total, %d new episodes",
+		success, failed, totalPodcasts, len(newEpisodes))
+
+	a.recordNewEpisodes(newEpisodes)
+	newSummary := formatNewEpisodeSummary(len(newEpisodes), podcastsWithNew)
+
 	// Save subscriptions
 	if err := a.subscriptions.Save(); err != nil {
 		log.Printf("Failed to save subscriptions: %v", err)
 		a.statusMessage = "Error saving subscriptions"
+	} else if background {
+		if newSummary != "" {
+			a.statusMessage = newSummary
+		}
 	} else {
 		// Show completion status
 		elapsed := time.Since(startTime).Round(time.Second)
-		failedCount := totalPodcasts - int(successCount)
-		if failedCount > 0 {
-			a.statusMessage = fmt.Sprintf("Refresh complete in %v: %d succeeded, %d failed",
-				elapsed, successCount, failedCount)
+		if failed > 0 {
+			a.statusMessage = fmt.Sprintf("Refresh complete in %v: %d succeeded, %d failed (H for details)",
+				elapsed, success, failed)
 		} else {
 			a.statusMessage = fmt.Sprintf("All %d podcasts refreshed successfully in %v",
-				successCount, elapsed)
+				success, elapsed)
+		}
+		if newSummary != "" {
+			a.statusMessage += "; " + newSummary
 		}
 	}
 
@@ -1476,14 +1771,15 @@ func (a *App) refreshFeeds() {
 // refreshSinglePodcast refreshes just one podcast's feed
 func (a *App) refreshSinglePodcast(podcast *models.Podcast) {
 	// Check if this podcast is already being refreshed
+	feedURL := podcast.URL
 	a.refreshMutex.Lock()
-	if a.activeRefreshes[podcast.URL] {
+	if a.activeRefreshes[feedURL] {
 		a.refreshMutex.Unlock()
 		a.statusMessage = fmt.Sprintf("%s is already refreshing", podcast.Title)
 		a.draw()
 		return
 	}
-	a.activeRefreshes[podcast.URL] = true
+	a.activeRefreshes[feedURL] = true
 	a.refreshMutex.Unlock()
 
 	// Acquire semaphore slot
@@ -1493,21 +1789,39 @@ func (a *App) refreshSinglePodcast(podcast *models.Podcast) {
 	defer func() {
 		<-a.refreshSemaphore
 		a.refreshMutex.Lock()
-		delete(a.activeRefreshes, podcast.URL)
+		delete(a.activeRefreshes, feedURL)
 		a.refreshMutex.Unlock()
 	}()
 
 	// Parse the feed
-	updated, err := feed.ParseFeed(podcast.URL)
+	result, err := feed.Refresh(podcast, a.feedOptions(podcast, feedURL))
 	if err != nil {
-		log.Printf("Failed to refresh single podcast '%s' from %s: %v", podcast.Title, podcast.URL, err)
+		log.Printf("Failed to refresh single podcast '%s' from %s: %v", podcast.Title, credentials.RedactURL(feedURL), err)
+		a.recordFeedFailure(podcast, err)
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save subscriptions: %v", err)
+		}
 		a.statusMessage = fmt.Sprintf("Failed to refresh %s: %v", podcast.Title, err)
+		a.podcasts.SetSubscriptions(a.subscriptions)
+		a.draw()
+		return
+	}
+
+	if result.NotModified {
+		podcast.RecordRefreshSuccess(time.Now(), http.StatusNotModified)
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save subscriptions: %v", err)
+		}
+		a.statusMessage = fmt.Sprintf("%s is unchanged", podcast.Title)
 		a.draw()
 		return
 	}
 
 	// Merge the updated data
-	a.mergePodcastData(podcast, updated)
+	added := a.mergePodcastData(podcast, result.Podcast)
+	podcast.RecordRefreshSuccess(time.Now(), http.StatusOK)
+	moved := a.applyFeedMove(podcast, result)
+	a.recordNewEpisodes(added)
 
 	// Save subscriptions
 	if err := a.subscriptions.Save(); err != nil {
@@ -1519,10 +1833,28 @@ func (a *App) refreshSinglePodcast(podcast *models.Podcast) {
 
 	// Update status and redraw
 	a.statusMessage = fmt.Sprintf("%s refreshed successfully", podcast.Title)
+	if moved {
+		a.statusMessage += "; feed moved to " + podcast.URL
+	}
+	if summary := formatNewEpisodeSummary(len(added), 1); summary != "" {
+		a.statusMessage += "; " + summary
+	}
 	a.draw()
 }
 
 func (a *App) saveEpisodePosition() {
+	if a.recordEpisodePosition() {
+		a.background(func() {
+			if err := a.subscriptions.Save(); err != nil {
+				log.Printf("Failed to save episode position: %v", err)
+			}
+		})
+	}
+}
+
+// recordEpisodePosition updates the playing episode with the player's position
+// without saving it, reporting whether there was one
+func (a *App) recordEpisodePosition() bool {
 	if a.currentEpisode != nil && a.player.GetState() != player.StateStopped {
 		if position, err := a.player.GetPosition(); err == nil {
 			log.Printf("Saving position for episode '%s': %v", a.currentEpisode.Title, position)
@@ -1558,7 +1890,7 @@ func (a *App) saveEpisodePosition() {
 						// Immediately update the UI to show the new duration
 						if a.currentView == a.episodes {
 							// Check for modals before updating
-							if a.helpDialog.IsVisible() || a.confirmDialog.IsVisible() {
+							if a.isModalVisible() {
 								a.draw()
 							} else {
 								a.episodes.UpdateCurrentEpisodePosition(a.screen)
@@ -1576,21 +1908,30 @@ func (a *App) saveEpisodePosition() {
 					}
 				}
 
+				// Remember when it was last heard, for the smart rewind on resuming
+				if a.player.IsPlaying() {
+					episode.LastPlayed = time.Now()
+				}
+
 				// Also update our local reference
 				a.currentEpisode.Position = position
 				if episode.Played {
 					a.currentEpisode.Played = true
 				}
 			}
-
-			// Save to disk
-			go func() {
-				if err := a.subscriptions.Save(); err != nil {
-					log.Printf("Failed to save episode position: %v", err)
-				}
-			}()
+			return true
 		}
 	}
+	return false
+}
+
+// rewoundPosition returns where to resume from a saved position: a little
+// before it when smart rewind is on, the more the longer since lastHeard
+func (a *App) rewoundPosition(position time.Duration, lastHeard time.Time) time.Duration {
+	if lastHeard.IsZero() {
+		return position
+	}
+	return max(position-a.settings.Rewind().Amount(time.Since(lastHeard)), 0)
 }
 
 // stopCurrentEpisode stops the current episode synchronously and updates status
@@ -1628,14 +1969,24 @@ func (a *App) stopCurrentEpisode() {
 
 func (a *App) playEpisode(episode *models.Episode) {
 	log.Printf("playEpisode called for: %s", episode.Title)
+	if !episode.Playable() {
+		a.statusMessage = "Text-only episode, nothing to play: " + episode.Title
+		return
+	}
 	log.Printf("Episode position at start of playEpisode: %v", episode.Position)
+	lastHeard := episode.LastPlayed
 
 	// Stop position ticker to ensure clean state transition
 	a.stopPositionTicker()
 
-	// Save position of current episode if switching
-	if a.currentEpisode != nil && a.currentEpisode.ID != episode.ID {
-		a.saveEpisodePosition()
+	// Save position of current episode if switching, once done changing the
+	// new one so the background save doesn't read it mid-change
+	if a.currentEpisode != nil && a.currentEpisode.ID != episode.ID && a.recordEpisodePosition() {
+		defer a.background(func() {
+			if err := a.subscriptions.Save(); err != nil {
+				log.Printf("Failed to save episode position: %v", err)
+			}
+		})
 	}
 
 	// Find the canonical episode in subscription data using the index
@@ -1692,7 +2043,11 @@ func (a *App) playEpisode(episode *models.Episode) {
 	log.Printf("Episode.Downloaded: %v, Episode.DownloadPath: %s", episode.Downloaded, episode.DownloadPath)
 
 	var isLocal bool
-	if a.downloadManager.IsEpisodeDownloaded(episode, podcastTitle) && episode.DownloadPath != "" {
+	if location, local := feed.PlaybackLocation(episode.URL); local {
+		// Episodes of local sources play in place
+		playURL = location
+		isLocal = true
+	} else if a.downloadManager.IsEpisodeDownloaded(episode, podcastTitle) && episode.DownloadPath != "" {
 		// Verify file exists and is valid
 		if _, err := os.Stat(episode.DownloadPath); err == nil {
 			playURL = episode.DownloadPath
@@ -1701,27 +2056,44 @@ func (a *App) playEpisode(episode *models.Episode) {
 		} else {
 			// File missing, fallback to streaming
 			log.Printf("Downloaded file missing for %s, streaming instead", episode.Title)
-			playURL = episode.URL
+			playURL = a.untrackedURL(episode.URL)
 			isLocal = false
 			// Reset download status if file is missing
 			episode.Downloaded = false
 			episode.DownloadPath = ""
 		}
 	} else {
-		playURL = episode.URL
+		playURL = a.untrackedURL(episode.URL)
 		isLocal = false
 		log.Printf("Playing from URL: %s", playURL)
 	}
 
+	// Start from the saved position, going back a little if it's been a while,
+	// or past the intro of a new episode. The player starts the file there, so
+	// the saved position stands until playback actually moves on from it.
+	log.Printf("Episode position check - Position: %v, Title: %s", episode.Position, episode.Title)
+	startAt := a.startPosition(episode, lastHeard)
+
+	a.applyAudioFilters()
+
 	// Use SwitchTrack for seamless switching between episodes
-	if err := a.player.SwitchTrack(playURL); err != nil {
+	if err := a.player.SwitchTrack(playURL, startAt); err != nil {
 		a.statusMessage = "Error: " + err.Error()
 		log.Printf("Failed to play episode: %v", err)
 		return
 	}
+	a.completionHandled.Store(false)
+	a.applyOutro()
+	a.preloadNext()
 
 	// Update status to show playing
 	playingStatus := "Playing: " + episode.Title
+	if startAt > 0 && episode.Position == 0 {
+		playingStatus = fmt.Sprintf("Playing: %s (skipped %s intro)", episode.Title, a.formatTime(startAt))
+	} else if startAt > 0 {
+		log.Printf("Resuming from position: %v", startAt)
+		playingStatus = fmt.Sprintf("Resumed: %s at %s", episode.Title, a.formatTime(startAt))
+	}
 	if isLocal {
 		playingStatus += " (local)"
 	}
@@ -1733,158 +2105,84 @@ func (a *App) playEpisode(episode *models.Episode) {
 	// Redraw to update episode highlighting now that player state has changed
 	a.draw()
 
-	// Resume from saved position if available
-	log.Printf("Episode position check - Position: %v, Title: %s", episode.Position, episode.Title)
-	if episode.Position > 0 && episode.Position < time.Hour*24 {
-		// Store the position to resume from (in case it gets modified)
-		resumePosition := episode.Position
-		go func() {
-			// Wait for mpv to fully load the file
-			maxWaitTime := 5 * time.Second
-			startTime := time.Now()
+	a.startPositionTicker()
 
+	// Check and update the duration once the player knows it, which it often
+	// already does
+	if !a.updateEpisodeDuration(episode) {
+		a.background(func() {
 			// Wait until player reports a valid duration (indicates file is loaded)
-			for time.Since(startTime) < maxWaitTime {
-				if duration, err := a.player.GetDuration(); err == nil && duration > 0 {
-					log.Printf("File loaded, duration: %v", duration)
-
-					// Update episode duration if it's unknown or different
-					durationDiff := a.currentEpisode.Duration - duration
-					if durationDiff < 0 {
-						durationDiff = -durationDiff
-					}
-
-					if a.currentEpisode.Duration == 0 || durationDiff > time.Second {
-						log.Printf("Updating duration for episode '%s': %v -> %v",
-							a.currentEpisode.Title, a.currentEpisode.Duration, duration)
-
-						// Update the episode in the actual subscription data using the index
-						if ep := a.subscriptions.GetEpisodeByID(a.currentEpisode.ID); ep != nil {
-							ep.Duration = duration
-						}
-
-						a.currentEpisode.Duration = duration
-
-						// Update the duration in the episode list directly
-						a.episodes.UpdateEpisodeDuration(a.currentEpisode.ID, duration)
-
-						// Immediately update the UI to show the new duration
-						if a.currentView == a.episodes {
-							// Check for modals before updating
-							if a.helpDialog.IsVisible() || a.confirmDialog.IsVisible() {
-								a.draw()
-							} else {
-								a.episodes.UpdateCurrentEpisodePosition(a.screen)
-								a.screen.Show()
-							}
-						}
-
-						// Save immediately to persist the duration
-						go func() {
-							if err := a.subscriptions.Save(); err != nil {
-								log.Printf("Failed to save episode duration: %v", err)
-							}
-						}()
-					}
-					break
-				}
+			deadline := time.Now().Add(5 * time.Second)
+			for time.Now().Before(deadline) {
 				time.Sleep(100 * time.Millisecond)
-			}
-
-			// Additional small delay to ensure mpv is ready for seeking
-			time.Sleep(200 * time.Millisecond)
-
-			// Seek to saved position
-			seekSeconds := int(resumePosition.Seconds())
-			log.Printf("Attempting to seek to position: %d seconds (%v)", seekSeconds, resumePosition)
-
-			// Try seeking multiple times if it fails initially
-			var err error
-			for attempts := 0; attempts < 3; attempts++ {
-				err = a.player.Seek(seekSeconds)
-				if err == nil {
-					log.Printf("Successfully resumed from position: %v on attempt %d", resumePosition, attempts+1)
-					a.statusMessage = fmt.Sprintf("Resumed: %s at %s",
-						episode.Title, a.formatTime(resumePosition))
-					// Update the episode position to match what we seeked to
-					a.currentEpisode.Position = resumePosition
-					// Start position ticker after successful seek
-					a.startPositionTicker()
-					break
+				if a.updateEpisodeDuration(episode) {
+					return
 				}
-				log.Printf("Seek attempt %d failed: %v", attempts+1, err)
-				time.Sleep(500 * time.Millisecond)
 			}
+		})
+	}
+}
 
-			if err != nil {
-				log.Printf("Failed to resume from position after 3 attempts: %v", err)
-				a.statusMessage = "Failed to resume from saved position"
-				// Start position ticker even if seek failed
-				a.startPositionTicker()
-			}
-		}()
-	} else {
-		log.Printf("Not resuming - Position: %v (either 0 or > 24h)", episode.Position)
-		// Start position ticker immediately if no resume needed
-		a.startPositionTicker()
-
-		// Check and update duration for episodes starting from beginning
-		go func() {
-			// Wait a bit for the player to load the file
-			time.Sleep(500 * time.Millisecond)
-
-			if duration, err := a.player.GetDuration(); err == nil && duration > 0 {
-				// Check if duration is significantly different
-				durationDiff := a.currentEpisode.Duration - duration
-				if durationDiff < 0 {
-					durationDiff = -durationDiff
-				}
-
-				if a.currentEpisode.Duration == 0 || durationDiff > time.Second {
-					log.Printf("Updating duration for episode '%s': %v -> %v",
-						a.currentEpisode.Title, a.currentEpisode.Duration, duration)
+// updateEpisodeDuration records the duration the player reports for the
+// episode being played, reporting false while the player doesn't know it yet
+func (a *App) updateEpisodeDuration(episode *models.Episode) bool {
+	// Another episode may have started, or this one finished, meanwhile
+	current := a.currentEpisode
+	if current == nil || current.ID != episode.ID {
+		return true
+	}
+	duration, err := a.player.GetDuration()
+	if err != nil || duration <= 0 {
+		return false
+	}
+	log.Printf("File loaded, duration: %v", duration)
 
-					// Update the episode in the actual subscription data
-					for _, podcast := range a.subscriptions.Podcasts {
-						for _, ep := range podcast.Episodes {
-							if ep.ID == a.currentEpisode.ID {
-								ep.Duration = duration
-								break
-							}
-						}
-					}
+	// Update episode duration if it's unknown or different
+	durationDiff := current.Duration - duration
+	if durationDiff < 0 {
+		durationDiff = -durationDiff
+	}
 
-					a.currentEpisode.Duration = duration
+	if current.Duration == 0 || durationDiff > time.Second {
+		log.Printf("Updating duration for episode '%s': %v -> %v",
+			current.Title, current.Duration, duration)
 
-					// Update the duration in the episode list directly
-					a.episodes.UpdateEpisodeDuration(a.currentEpisode.ID, duration)
+		// Update the episode in the actual subscription data using the index
+		if ep := a.subscriptions.GetEpisodeByID(current.ID); ep != nil {
+			ep.Duration = duration
+		}
 
-					// Update the episode list view's reference
-					a.episodes.SetCurrentEpisode(a.currentEpisode)
+		current.Duration = duration
 
-					// Immediately update the UI to show the new duration
-					if a.currentView == a.episodes {
-						// Check for modals before updating
-						if a.helpDialog.IsVisible() || a.confirmDialog.IsVisible() {
-							a.draw()
-						} else {
-							a.episodes.UpdateCurrentEpisodePosition(a.screen)
-							a.screen.Show()
-						}
-					}
+		// Update the duration in the episode list directly
+		a.episodes.UpdateEpisodeDuration(current.ID, duration)
 
-					// Save immediately to persist the duration
-					if err := a.subscriptions.Save(); err != nil {
-						log.Printf("Failed to save episode duration: %v", err)
-					}
-				}
+		// Immediately update the UI to show the new duration
+		if a.currentView == a.episodes {
+			// Check for modals before updating
+			if a.isModalVisible() {
+				a.draw()
+			} else {
+				a.episodes.UpdateCurrentEpisodePosition(a.screen)
+				a.screen.Show()
 			}
-		}()
+		}
+
+		// Save immediately to persist the duration
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save episode duration: %v", err)
+		}
 	}
+	return true
 }
 
 // restartEpisode starts playing an episode from the beginning, ignoring saved position
 func (a *App) restartEpisode(episode *models.Episode) {
+	if !episode.Playable() {
+		a.statusMessage = "Text-only episode, nothing to play: " + episode.Title
+		return
+	}
+
 	// Save position of current episode if it's different
 	if a.currentEpisode != nil && a.currentEpisode.ID != episode.ID {
 		a.saveEpisodePosition()
@@ -1929,7 +2227,11 @@ func (a *App) restartEpisode(episode *models.Episode) {
 	// Use comprehensive download check to get latest state
 	var playURL string
 	var isLocal bool
-	if a.downloadManager.IsEpisodeDownloaded(episode, podcastTitle) && episode.DownloadPath != "" {
+	if location, local := feed.PlaybackLocation(episode.URL); local {
+		// Episodes of local sources play in place
+		playURL = location
+		isLocal = true
+	} else if a.downloadManager.IsEpisodeDownloaded(episode, podcastTitle) && episode.DownloadPath != "" {
 		// Verify file exists and is valid
 		if _, err := os.Stat(episode.DownloadPath); err == nil {
 			playURL = episode.DownloadPath
@@ -1937,23 +2239,28 @@ func (a *App) restartEpisode(episode *models.Episode) {
 		} else {
 			// File missing, fallback to streaming
 			log.Printf("Downloaded file missing for %s, streaming instead", episode.Title)
-			playURL = episode.URL
+			playURL = a.untrackedURL(episode.URL)
 			isLocal = false
 			// Reset download status if file is missing
 			episode.Downloaded = false
 			episode.DownloadPath = ""
 		}
 	} else {
-		playURL = episode.URL
+		playURL = a.untrackedURL(episode.URL)
 		isLocal = false
 	}
 
+	a.applyAudioFilters()
+
 	// Use SwitchTrack for seamless switching
-	if err := a.player.SwitchTrack(playURL); err != nil {
+	if err := a.player.SwitchTrack(playURL, 0); err != nil {
 		a.statusMessage = "Error: " + err.Error()
 		log.Printf("Failed to restart episode: %v", err)
 		return
 	}
+	a.completionHandled.Store(false)
+	a.applyOutro()
+	a.preloadNext()
 
 	// Update status to show playing
 	playingStatus := "Playing: " + episode.Title
@@ -1979,6 +2286,12 @@ func (a *App) restartEpisode(episode *models.Episode) {
 
 // addToQueue adds an episode to the playback queue
 func (a *App) addToQueue(episode *models.Episode) {
+	if !episode.Playable() {
+		a.statusMessage = "Text-only episodes can't be queued"
+		a.draw()
+		return
+	}
+
 	// Check if episode is already in queue
 	if position := a.subscriptions.GetQueuePosition(episode.ID); position > 0 {
 		// Episode is already in queue, just notify the user
@@ -1998,6 +2311,7 @@ func (a *App) addToQueue(episode *models.Episode) {
 	if err := a.subscriptions.Save(); err != nil {
 		log.Printf("Failed to save queue: %v", err)
 	}
+	a.preloadNext()
 
 	// Update queue view if it's current
 	if a.currentView == a.queue {
@@ -2037,16 +2351,63 @@ func (a *App) handleEpisodeCompletion() {
 		a.transitionInProgress = false
 	}()
 	
-	// Check if there's a next episode in queue
+	// The player has already stopped, so record the finished episode here
+	if episode := a.subscriptions.GetEpisodeByID(a.currentEpisode.ID); episode != nil {
+		episode.Played = true
+		a.currentEpisode.Played = true
+	}
+
+	// Check if there's a next episode in queue, unless the sleep timer ends here
+	sleeping := a.sleepAtEndOfEpisode()
 	nextEpisode := a.subscriptions.GetNextInQueue()
-	if nextEpisode != nil {
+	if nextEpisode != nil && !sleeping {
 		log.Printf("Next episode in queue: %s", nextEpisode.Title)
 		a.playNextInQueue()
 	} else {
 		// No more episodes in queue
 		log.Printf("Episode ended, no more episodes in queue")
-		a.stopCurrentEpisode()
+		a.statusMessage = "Finished: " + a.currentEpisode.Title
+		if sleeping {
+			// The finished episode leaves the queue, but the next one waits
+			a.subscriptions.RemoveFromQueue(a.currentEpisode.ID)
+			if a.currentView == a.queue {
+				a.queue.refresh()
+			}
+			a.statusMessage = "Sleep timer: stopped after " + a.currentEpisode.Title
+		}
+		a.stopPositionTicker()
+		a.currentEpisode = nil
+		a.currentPodcast = nil
+		a.episodes.SetCurrentEpisode(nil)
+		a.queue.SetCurrentEpisode(nil)
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save finished episode: %v", err)
+		}
+		a.draw()
+	}
+}
+
+// handlePlaybackError reports that the current episode stopped because it
+// could not be played. Unlike a completion, the episode keeps its saved
+// position and stays in the queue, which does not advance.
+func (a *App) handlePlaybackError(err error) {
+	a.transitionMutex.Lock()
+	defer a.transitionMutex.Unlock()
+
+	if a.currentEpisode == nil {
+		a.statusMessage = fmt.Sprintf("Error: %v", err)
+		a.draw()
+		return
 	}
+
+	log.Printf("Playback of %s failed: %v", a.currentEpisode.Title, err)
+	a.statusMessage = fmt.Sprintf("Error playing %s: %v", a.currentEpisode.Title, err)
+	a.stopPositionTicker()
+	a.currentEpisode = nil
+	a.currentPodcast = nil
+	a.episodes.SetCurrentEpisode(nil)
+	a.queue.SetCurrentEpisode(nil)
+	a.draw()
 }
 
 // playNextInQueue plays the next episode in the queue
@@ -2175,6 +2536,7 @@ func (a *App) removeFromQueue(episode *models.Episode) {
 		a.statusMessage = "Removed from queue"
 		a.draw()
 	}
+	a.preloadNext()
 }
 
 // handleDownloadProgress handles download progress updates
@@ -2213,6 +2575,16 @@ func (a *App) handleDownloadProgress() {
 
 // downloadEpisode starts downloading an episode
 func (a *App) downloadEpisode(episode *models.Episode) {
+	if !episode.Playable() {
+		a.statusMessage = "Text-only episode, nothing to download: " + episode.Title
+		return
+	}
+	// Episodes of local sources are already on disk
+	if !feed.CapabilitiesOf(episode.URL).Downloads {
+		a.statusMessage = "Nothing to download: " + episode.Title
+		return
+	}
+
 	// Get podcast title for comprehensive download check
 	podcastTitle := ""
 	if a.currentView == a.episodes {
@@ -2353,6 +2725,7 @@ func (a *App) confirmPodcastDeletion(podcast *models.Podcast) {
 			a.draw() // Show status immediately
 
 			a.subscriptions.Remove(podcast.URL)
+			a.forgetFeedCredentials(podcast)
 			if err := a.subscriptions.Save(); err != nil {
 				a.statusMessage = "Error saving: " + err.Error()
 				log.Printf("Failed to save subscriptions after deletion: %v", err)
@@ -2376,6 +2749,25 @@ func (a *App) confirmPodcastDeletion(podcast *models.Podcast) {
 		})
 }
 
+// showFeedHealth shows the refresh history of the selected podcast, offering a retry
+func (a *App) showFeedHealth() {
+	var podcast *models.Podcast
+	if a.currentView == a.podcasts {
+		podcast = a.podcasts.GetSelected()
+	} else if a.currentView == a.episodes {
+		podcast = a.episodes.GetCurrentPodcast()
+	}
+	if podcast == nil {
+		a.statusMessage = "No podcast selected"
+		return
+	}
+
+	a.healthDialog.Show(podcast, func() {
+		a.statusMessage = fmt.Sprintf("Refreshing %s...", podcast.Title)
+		go a.refreshSinglePodcast(podcast)
+	})
+}
+
 // selectEpisodeInList selects a specific episode in the episode list view
 func (a *App) selectEpisodeInList(episodeID string) {
 	if !a.episodes.SelectEpisodeByID(episodeID) {
@@ -2451,7 +2843,8 @@ func (a *App) clearStatusMessage() {
 
 	// Don't clear recent refresh status
 	if strings.Contains(a.statusMessage, "Refreshing feeds") ||
-		strings.Contains(a.statusMessage, "feeds refreshed") {
+		strings.Contains(a.statusMessage, "feeds refreshed") ||
+		strings.Contains(a.statusMessage, "new episode") {
 		return // Keep refresh status visible
 	}
 
@@ -2459,8 +2852,41 @@ func (a *App) clearStatusMessage() {
 	a.statusMessage = ""
 }
 
-// mergePodcastData merges updated podcast data with existing data, preserving user state
-func (a *App) mergePodcastData(existing *models.Podcast, updated *models.Podcast) {
+// recordFeedFailure records a failed refresh, retiring feeds the server reports as gone
+func (a *App) recordFeedFailure(podcast *models.Podcast, err error) {
+	now := time.Now()
+	status := feed.StatusCode(err)
+	podcast.RecordRefreshFailure(now, err.Error(), status)
+	if status == http.StatusGone {
+		log.Printf("Feed for '%s' is gone, retiring it: %s", podcast.Title, credentials.RedactURL(podcast.URL))
+		podcast.Retire(now)
+	}
+}
+
+// applyFeedMove updates the podcast's URL when its feed has permanently moved.
+// Episode IDs are unaffected because they keep using the original URL.
+func (a *App) applyFeedMove(podcast *models.Podcast, result *feed.Result) bool {
+	newURL := result.NewLocation()
+	if newURL == "" || newURL == podcast.URL {
+		return false
+	}
+	if other := a.subscriptions.FindByURL(newURL); other != nil && other != podcast {
+		log.Printf("Not moving '%s' to %s: already subscribed as '%s'", podcast.Title, credentials.RedactURL(newURL), other.Title)
+		return false
+	}
+
+	log.Printf("Feed for '%s' moved from %s to %s", podcast.Title, credentials.RedactURL(podcast.URL), credentials.RedactURL(newURL))
+	oldURL := podcast.URL
+	if !podcast.MoveTo(newURL) {
+		return false
+	}
+	a.moveFeedCredential(oldURL, newURL)
+	return true
+}
+
+// mergePodcastData merges updated podcast data with existing data, preserving user state.
+// It returns the episodes that were not previously known.
+func (a *App) mergePodcastData(existing *models.Podcast, updated *models.Podcast) []*models.Episode {
 	// Update podcast metadata
 	existing.Title = updated.Title
 	existing.Description = updated.Description
@@ -2468,10 +2894,18 @@ func (a *App) mergePodcastData(existing *models.Podcast, updated *models.Podcast
 	existing.ImageURL = updated.ImageURL
 	existing.Author = updated.Author
 	existing.LastUpdated = updated.LastUpdated
+	existing.ParseIssues = updated.ParseIssues
+	existing.ETag = updated.ETag
+	existing.LastModified = updated.LastModified
+	existing.SelfURL = updated.SelfURL
 
 	// Create maps for existing episodes - by ID and by URL+date for fallback
 	existingEpisodesById := make(map[string]*models.Episode)
 	existingEpisodesByKey := make(map[string]*models.Episode)
+	// Episodes whose date couldn't be parsed before, matched by URL once it can be
+	undatedEpisodesByURL := make(map[string]*models.Episode)
+	// Episodes the feed identifies with a GUID, matched even if their URL changes
+	existingEpisodesByGUID := make(map[string]*models.Episode)
 
 	for _, episode := range existing.Episodes {
 		if episode.ID != "" {
@@ -2480,10 +2914,18 @@ func (a *App) mergePodcastData(existing *models.Podcast, updated *models.Podcast
 		// Create fallback key using URL and publish date
 		key := episode.URL + "|" + episode.PublishDate.Format("2006-01-02T15:04:05Z")
 		existingEpisodesByKey[key] = episode
+		if episode.PublishDate.IsZero() && episode.URL != "" {
+			undatedEpisodesByURL[episode.URL] = episode
+		}
+		if episode.GUID != "" {
+			existingEpisodesByGUID[episode.GUID] = episode
+		}
 	}
 
 	// Process updated episodes
 	var mergedEpisodes []*models.Episode
+	var addedEpisodes []*models.Episode
+	matched := make(map[*models.Episode]bool)
 	for _, newEpisode := range updated.Episodes {
 		var existingEp *models.Episode
 		var found bool
@@ -2498,14 +2940,34 @@ func (a *App) mergePodcastData(existing *models.Podcast, updated *models.Podcast
 			key := newEpisode.URL + "|" + newEpisode.PublishDate.Format("2006-01-02T15:04:05Z")
 			existingEp, found = existingEpisodesByKey[key]
 		}
+		if !found && !newEpisode.PublishDate.IsZero() {
+			if existingEp, found = undatedEpisodesByURL[newEpisode.URL]; found {
+				delete(undatedEpisodesByURL, newEpisode.URL)
+			}
+		}
+		if !found && newEpisode.GUID != "" {
+			// Some feeds reuse GUIDs; never merge two episodes into one
+			if ep, ok := existingEpisodesByGUID[newEpisode.GUID]; ok && !matched[ep] {
+				existingEp, found = ep, true
+			}
+		}
 
 		if found {
-			// Episode already exists - merge data, preserving user state
-			existingEp.ID = newEpisode.ID // Update ID if it was empty
+			// Episode already exists - merge data, preserving user state.
+			// Keep its ID even if the feed's data now yields a different one, since the
+			// queue and downloads refer to it.
+			if existingEp.ID == "" {
+				existingEp.ID = newEpisode.ID
+			}
+			existingEp.GUID = newEpisode.GUID
 			existingEp.Title = newEpisode.Title
 			existingEp.Description = newEpisode.Description
 			existingEp.ConvertedDescription = newEpisode.ConvertedDescription
 			existingEp.URL = newEpisode.URL
+			existingEp.Enclosures = newEpisode.Enclosures
+			existingEp.TextOnly = newEpisode.TextOnly
+			existingEp.FileSize = newEpisode.FileSize
+			existingEp.Artist = newEpisode.Artist
 			existingEp.PublishDate = newEpisode.PublishDate
 
 			// Update duration only if existing is unknown or new duration is more accurate
@@ -2517,19 +2979,35 @@ func (a *App) mergePodcastData(existing *models.Podcast, updated *models.Podcast
 
 			// Keep existing user state: Position, Played, Downloaded, etc.
 			mergedEpisodes = append(mergedEpisodes, existingEp)
+			matched[existingEp] = true
 		} else {
 			// New episode - add it as-is
 			mergedEpisodes = append(mergedEpisodes, newEpisode)
+			addedEpisodes = append(addedEpisodes, newEpisode)
+		}
+	}
+
+	// Paginated feeds only list recent episodes on the first page, so keep the
+	// older ones fetched from the rest of the history
+	existing.Paginated = updated.Paginated
+	if updated.Paginated {
+		for _, episode := range existing.Episodes {
+			if !matched[episode] {
+				mergedEpisodes = append(mergedEpisodes, episode)
+			}
 		}
 	}
 
 	// Replace episodes with merged list
 	existing.Episodes = mergedEpisodes
+	existing.ApplyEnclosurePreference()
 
 	// Update the episode index for all new/modified episodes
 	for _, episode := range mergedEpisodes {
 		a.subscriptions.UpdateEpisodeIndex(episode, existing)
 	}
+
+	return addedEpisodes
 }
 
 // startPositionTicker starts a ticker that updates the UI periodically when playing
@@ -2538,10 +3016,11 @@ func (a *App) startPositionTicker() {
 	a.stopPositionTicker()
 
 	// Create new ticker for position updates (every 500ms)
-	a.positionTicker = time.NewTicker(500 * time.Millisecond)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	a.positionTicker = ticker
 
 	go func() {
-		for range a.positionTicker.C {
+		for range ticker.C {
 			// Only update if playing
 			if a.player.GetState() == player.StatePlaying {
 				// Send non-blocking update signal
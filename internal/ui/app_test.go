@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/models"
+	"github.com/csams/podcast-tui/internal/player"
+	"github.com/gdamore/tcell/v2"
+)
+
+// newTestApp returns an app drawing to a simulated screen and playing with a
+// fake backend. Its config and downloads live in a temporary directory.
+func newTestApp(t *testing.T) (*App, *player.Fake) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("HOME", dir)
+
+	fake := player.NewFake()
+	app := newApp(fake)
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("Failed to init screen: %v", err)
+	}
+	screen.SetSize(120, 40)
+	app.screen = screen
+	app.setup()
+
+	// A background save by an earlier test's app can land in this config dir
+	// before it is loaded, so start without subscriptions regardless
+	subs := &models.Subscriptions{}
+	app.subscriptions = subs
+	app.podcasts.SetSubscriptions(subs)
+	app.episodes.SetSubscriptions(subs)
+	app.queue.SetSubscriptions(subs)
+	app.directoryView.SetSubscriptions(subs)
+
+	t.Cleanup(func() {
+		app.tasks.Wait()
+		app.shutdown()
+		screen.Fini()
+	})
+	return app, fake
+}
+
+// addTestPodcast subscribes to a podcast with n episodes of the given duration
+func addTestPodcast(app *App, fake *player.Fake, n int, duration time.Duration) []*models.Episode {
+	podcast := &models.Podcast{Title: "Test Podcast", URL: "https://example.com/feed.xml"}
+	for i := 1; i <= n; i++ {
+		episode := &models.Episode{
+			Title:       fmt.Sprintf("Episode %d", i),
+			URL:         fmt.Sprintf("https://example.com/%d.mp3", i),
+			PublishDate: time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC),
+		}
+		episode.GenerateID(podcast.URL)
+		podcast.Episodes = append(podcast.Episodes, episode)
+		fake.SetDuration(episode.URL, duration)
+	}
+	app.subscriptions.Add(podcast)
+	return podcast.Episodes
+}
+
+// settle handles the player's pending events on the test goroutine, as
+// handlePlayerEvents would, and waits for the work they start in the background
+func settle(app *App, fake *player.Fake) {
+	for {
+		select {
+		case event := <-fake.Events():
+			app.handlePlayerEvent(event)
+		default:
+			app.tasks.Wait()
+			if len(fake.Events()) == 0 {
+				return
+			}
+		}
+	}
+}
+
+func TestApp_QueueAutoAdvance(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 3, 10*time.Minute)
+
+	app.addToQueue(episodes[0])
+	app.addToQueue(episodes[1])
+	if fake.URL() != episodes[0].URL {
+		t.Fatalf("Expected queueing to an empty queue to start playback, playing %q", fake.URL())
+	}
+
+	fake.Finish()
+	settle(app, fake)
+	if fake.URL() != episodes[1].URL {
+		t.Fatalf("Expected the second episode to play, playing %q", fake.URL())
+	}
+	if !episodes[0].Played {
+		t.Error("Expected the finished episode to be marked played")
+	}
+	if len(app.subscriptions.Queue) != 1 || app.subscriptions.GetQueuePosition(episodes[1].ID) != 1 {
+		t.Errorf("Expected only the playing episode left in the queue, got %d entries", len(app.subscriptions.Queue))
+	}
+
+	fake.Finish()
+	settle(app, fake)
+	if app.statusMessage != "Queue finished" {
+		t.Errorf("Expected the queue to finish, got %q", app.statusMessage)
+	}
+	if app.currentEpisode != nil || len(app.subscriptions.Queue) != 0 {
+		t.Errorf("Expected nothing playing or queued, got %v and %d entries", app.currentEpisode, len(app.subscriptions.Queue))
+	}
+	if got := fake.Loaded(); len(got) != 2 {
+		t.Errorf("Expected 2 episodes to be played, got %v", got)
+	}
+}
+
+func TestApp_CompletionOutsideQueue(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 2, 5*time.Minute)
+
+	app.playEpisode(episodes[1])
+	fake.Advance(time.Minute)
+	if episodes[1].Played {
+		t.Fatal("Expected the episode not to be played before it ends")
+	}
+
+	fake.Finish()
+	settle(app, fake)
+	if app.currentEpisode != nil || !episodes[1].Played || app.statusMessage != "Finished: Episode 2" {
+		t.Errorf("Expected the episode to be finished, got played=%v status=%q", episodes[1].Played, app.statusMessage)
+	}
+	if len(fake.Loaded()) != 1 {
+		t.Errorf("Expected nothing else to play, got %v", fake.Loaded())
+	}
+
+	// Stopping by hand is not a completion
+	app.playEpisode(episodes[0])
+	fake.Advance(time.Minute)
+	app.stopCurrentEpisode()
+	if episodes[0].Played || episodes[0].Position != time.Minute {
+		t.Errorf("Expected a stopped episode to keep its position, got played=%v position=%v", episodes[0].Played, episodes[0].Position)
+	}
+}
+
+func TestApp_PositionSaving(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 2, 30*time.Minute)
+
+	app.playEpisode(episodes[0])
+	fake.Advance(90 * time.Second)
+
+	// Switching episodes saves the position of the one being left
+	app.playEpisode(episodes[1])
+	if episodes[0].Position != 90*time.Second {
+		t.Errorf("Expected position 1m30s to be saved, got %v", episodes[0].Position)
+	}
+	if episodes[0].Duration != 30*time.Minute {
+		t.Errorf("Expected the duration reported by the player to be saved, got %v", episodes[0].Duration)
+	}
+
+	settle(app, fake)
+	path := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "podcast-tui", "subscriptions.json")
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"position": 90000000000`) {
+		t.Errorf("Expected the position to be saved, got %v", err)
+	}
+
+	// Playing it again resumes where it was left
+	app.playEpisode(episodes[0])
+	settle(app, fake)
+	if position, _ := fake.GetPosition(); position != 90*time.Second {
+		t.Errorf("Expected the saved position to be restored, at %v", position)
+	}
+
+	// Positions past 95% mark the episode played
+	fake.Advance(28 * time.Minute)
+	app.saveEpisodePosition()
+	settle(app, fake)
+	if !episodes[0].Played {
+		t.Error("Expected an episode saved at 95% to be marked played")
+	}
+}
+
+func TestApp_PlaybackErrors(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 1, time.Minute)
+
+	fake.FailLoading(episodes[0].URL, errors.New("connection refused"))
+	app.playEpisode(episodes[0])
+	if !strings.Contains(app.statusMessage, "connection refused") {
+		t.Errorf("Expected the load error in the status bar, got %q", app.statusMessage)
+	}
+	if fake.GetState() != player.StateStopped {
+		t.Error("Expected nothing to be playing")
+	}
+
+	textOnly := &models.Episode{Title: "Announcement", TextOnly: true}
+	app.playEpisode(textOnly)
+	if len(fake.Loaded()) != 0 || !strings.Contains(app.statusMessage, "Text-only") {
+		t.Errorf("Expected text-only episodes not to play, status %q", app.statusMessage)
+	}
+}
+
+func TestApp_PlaybackFailure(t *testing.T) {
+	app, fake := newTestApp(t)
+	queued := addTestPodcast(app, fake, 2, 10*time.Minute)
+
+	// A file that fails keeps its saved position and does not advance the queue
+	queued[0].Position = 90 * time.Second
+	app.addToQueue(queued[0])
+	app.addToQueue(queued[1])
+	fake.Fail(errors.New("playback failed: network error"))
+	settle(app, fake)
+	if app.currentEpisode != nil {
+		t.Error("Expected nothing playing after the error")
+	}
+	if !strings.Contains(app.statusMessage, "network error") {
+		t.Errorf("Expected the error in the status bar, got %q", app.statusMessage)
+	}
+	if queued[0].Played || queued[0].Position != 90*time.Second {
+		t.Errorf("Expected the failed episode to keep its place, got played=%v position=%v", queued[0].Played, queued[0].Position)
+	}
+	if got := fake.Loaded(); len(got) != 1 || len(app.subscriptions.Queue) != 2 {
+		t.Errorf("Expected the queue not to advance, loaded %v with %d queued", got, len(app.subscriptions.Queue))
+	}
+
+	// So does a stream that fails only after opening for a while, with the
+	// position ticker saving the position meanwhile
+	queued[1].Position = 3 * time.Minute
+	app.playEpisode(queued[1])
+	app.updateCurrentPosition()
+	fake.Fail(errors.New("playback failed: stream timed out"))
+	settle(app, fake)
+	if app.currentEpisode != nil || queued[1].Position != 3*time.Minute {
+		t.Errorf("Expected the slow stream to keep its position, got %v", queued[1].Position)
+	}
+}
+
+func TestApp_SmartRewind(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 2, time.Hour)
+	app.settings.SmartRewind = true
+	fake.SetSmartRewind(app.settings.Rewind())
+
+	// Resuming after a long pause goes back a little
+	app.playEpisode(episodes[0])
+	fake.Advance(10 * time.Minute)
+	app.saveEpisodePosition()
+	settle(app, fake)
+	if time.Since(episodes[0].LastPlayed) > time.Second {
+		t.Errorf("Expected saving while playing to record when the episode was heard, got %v", episodes[0].LastPlayed)
+	}
+	fake.Pause()
+	fake.Advance(2 * time.Hour)
+	fake.Resume()
+	if position, _ := fake.GetPosition(); position != 9*time.Minute+30*time.Second {
+		t.Errorf("Expected to resume 30s back, at %v", position)
+	}
+
+	// So does resuming a saved position heard long ago
+	episodes[1].Position = 20 * time.Minute
+	episodes[1].LastPlayed = time.Now().Add(-time.Minute)
+	app.playEpisode(episodes[1])
+	settle(app, fake)
+	if position, _ := fake.GetPosition(); position != 20*time.Minute-3*time.Second {
+		t.Errorf("Expected to resume 3s back, at %v", position)
+	}
+}
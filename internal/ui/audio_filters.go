@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/csams/podcast-tui/internal/models"
+	"github.com/csams/podcast-tui/internal/player"
+)
+
+const filterUsage = "Usage: filter [podcast] silence|normalize|voice [on|off|default]"
+
+// audioFiltersFor returns the filters to play podcast with: the global
+// setting, with the podcast's own choices taking precedence
+func (a *App) audioFiltersFor(podcast *models.Podcast) player.AudioFilters {
+	var filters player.AudioFilters
+	for _, name := range a.settings.AudioFilters {
+		filters.Set(name, true)
+	}
+	if podcast != nil {
+		for name, on := range podcast.AudioFilters {
+			filters.Set(name, on)
+		}
+	}
+	return filters
+}
+
+// applyAudioFilters sets the player's filters for the podcast being played,
+// unless they are already in use, as changing them interrupts the sound
+func (a *App) applyAudioFilters() {
+	filters := a.audioFiltersFor(a.currentPodcast)
+	if filters == a.player.GetAudioFilters() {
+		return
+	}
+	if err := a.player.SetAudioFilters(filters); err != nil {
+		log.Printf("Failed to apply audio filters: %v", err)
+		a.statusMessage = "Audio filter error: " + err.Error()
+	}
+}
+
+// setAudioFilter handles ":filter [podcast] <name> [on|off|default]". Without
+// "podcast" it changes the global setting, otherwise the selected podcast's
+// override, which "default" removes. Without on or off the filter is toggled,
+// and without arguments the filters in effect are shown.
+func (a *App) setAudioFilter(args []string) {
+	if len(args) == 0 {
+		a.statusMessage = a.describeAudioFilters()
+		return
+	}
+
+	var podcast *models.Podcast
+	if strings.ToLower(args[0]) == "podcast" {
+		if podcast = a.commandTarget(); podcast == nil {
+			a.statusMessage = "Select a podcast first"
+			return
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 || len(args) > 2 {
+		a.statusMessage = filterUsage
+		return
+	}
+
+	name := strings.ToLower(args[0])
+	if !player.ValidFilter(name) {
+		a.statusMessage = filterUsage
+		return
+	}
+	on := !a.audioFiltersFor(podcast).Enabled(name)
+	setting := "toggle"
+	if len(args) == 2 {
+		setting = strings.ToLower(args[1])
+	}
+	switch setting {
+	case "toggle":
+	case "on":
+		on = true
+	case "off":
+		on = false
+	case "default":
+		if podcast == nil {
+			a.statusMessage = filterUsage
+			return
+		}
+	default:
+		a.statusMessage = filterUsage
+		return
+	}
+
+	if podcast != nil {
+		if setting == "default" {
+			delete(podcast.AudioFilters, name)
+		} else {
+			if podcast.AudioFilters == nil {
+				podcast.AudioFilters = make(map[string]bool)
+			}
+			podcast.AudioFilters[name] = on
+		}
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save audio filters: %v", err)
+		}
+	} else {
+		a.settings.AudioFilters = slices.DeleteFunc(a.settings.AudioFilters, func(n string) bool { return n == name })
+		if on {
+			a.settings.AudioFilters = append(a.settings.AudioFilters, name)
+		}
+		if err := SaveSettings(a.configDir, a.settings); err != nil {
+			log.Printf("Failed to save audio filters: %v", err)
+		}
+	}
+
+	a.statusMessage = a.describeAudioFilters()
+	if a.currentEpisode != nil && (podcast == nil || podcast == a.currentPodcast) {
+		a.applyAudioFilters()
+	}
+}
+
+// describeAudioFilters tells which filters are on globally, and for the
+// selected podcast if it overrides any
+func (a *App) describeAudioFilters() string {
+	description := "Audio filters: " + describeFilterNames(a.audioFiltersFor(nil))
+	if podcast := a.commandTarget(); podcast != nil && len(podcast.AudioFilters) > 0 {
+		description += fmt.Sprintf(" | %s: %s", podcast.Title, describeFilterNames(a.audioFiltersFor(podcast)))
+	}
+	return description
+}
+
+func describeFilterNames(filters player.AudioFilters) string {
+	if names := filters.Names(); len(names) > 0 {
+		return strings.Join(names, ", ")
+	}
+	return "none"
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/player"
+)
+
+func TestApp_AudioFilters(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 1, time.Hour)
+	podcast := app.subscriptions.Podcasts[0]
+	app.episodes.SetPodcast(podcast)
+	app.currentView = app.episodes
+	app.playEpisode(episodes[0])
+
+	// Global filters apply right away and are saved
+	app.setAudioFilter([]string{"silence"})
+	app.setAudioFilter([]string{"normalize", "on"})
+	if got := fake.GetAudioFilters(); got != (player.AudioFilters{SkipSilence: true, Normalize: true}) {
+		t.Errorf("Expected silence and normalize on, got %+v", got)
+	}
+	if !strings.Contains(app.formatPlayerStatus(200), "[AF:silence+normalize]") {
+		t.Errorf("Expected the filters in the status bar, got %q", app.formatPlayerStatus(200))
+	}
+	if narrow := app.formatPlayerStatus(80); !strings.Contains(narrow, "[AF]") {
+		t.Errorf("Expected a compact filter indicator in a narrow status bar, got %q", narrow)
+	}
+	saved, err := LoadSettings(app.configDir)
+	if err != nil || len(saved.AudioFilters) != 2 {
+		t.Errorf("Expected the global filters saved, got %v (%v)", saved, err)
+	}
+
+	// The podcast's own choices take precedence
+	app.setAudioFilter([]string{"podcast", "silence", "off"})
+	app.setAudioFilter([]string{"podcast", "voice"})
+	if got := fake.GetAudioFilters(); got != (player.AudioFilters{Normalize: true, VoiceBoost: true}) {
+		t.Errorf("Expected the podcast's overrides, got %+v", got)
+	}
+	if !podcast.AudioFilters[player.FilterVoiceBoost] || podcast.AudioFilters[player.FilterSkipSilence] {
+		t.Errorf("Expected the overrides stored on the podcast, got %v", podcast.AudioFilters)
+	}
+
+	app.setAudioFilter([]string{"podcast", "silence", "default"})
+	app.setAudioFilter([]string{"normalize"})
+	if got := fake.GetAudioFilters(); got != (player.AudioFilters{SkipSilence: true, VoiceBoost: true}) {
+		t.Errorf("Expected the global silence filter back, got %+v", got)
+	}
+
+	app.setAudioFilter([]string{"reverb"})
+	if app.statusMessage != filterUsage {
+		t.Errorf("Expected a usage message, got %q", app.statusMessage)
+	}
+}
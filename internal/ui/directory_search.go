@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/directory"
+)
+
+// directorySearchTimeout bounds a directory search
+const directorySearchTimeout = 30 * time.Second
+
+// directoryConfig returns the configuration of the podcast directory, with the
+// Podcast Index API key from the credentials, or else from the
+// PODCASTINDEX_API_KEY and PODCASTINDEX_API_SECRET environment variables
+func (a *App) directoryConfig() directory.Config {
+	cfg := a.settings.DirectoryConfig()
+	if key := a.credentials.APIKey(directory.ProviderPodcastIndex); key != nil {
+		cfg.APIKey = key.Key
+		cfg.APISecret = key.Secret
+	}
+	if cfg.APIKey == "" {
+		cfg.APIKey = os.Getenv("PODCASTINDEX_API_KEY")
+	}
+	if cfg.APISecret == "" {
+		cfg.APISecret = os.Getenv("PODCASTINDEX_API_SECRET")
+	}
+	return cfg
+}
+
+// searchDirectory handles ":directory <query>", showing the matching podcasts
+// from the configured directory
+func (a *App) searchDirectory(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		a.statusMessage = "Usage: directory <search terms>"
+		return
+	}
+
+	provider, err := directory.New(a.directoryConfig())
+	if err != nil {
+		a.statusMessage = "Directory error: " + err.Error()
+		return
+	}
+
+	a.statusMessage = fmt.Sprintf("Searching %s for %q...", provider.Name(), query)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), directorySearchTimeout)
+		defer cancel()
+
+		results, err := provider.Search(ctx, query, directory.DefaultLimit)
+		if err != nil {
+			log.Printf("Directory search for %q failed: %v", query, err)
+			a.statusMessage = "Directory search failed: " + err.Error()
+			a.draw()
+			return
+		}
+
+		log.Printf("Directory search for %q returned %d results", query, len(results))
+		a.directoryView.SetResults(provider.Name(), query, results)
+		if a.currentView != a.directoryView {
+			a.previousView = a.currentView
+			a.currentView = a.directoryView
+		}
+		a.statusMessage = fmt.Sprintf("%d results; Enter to subscribe, Esc to go back", len(results))
+		a.draw()
+	}()
+}
+
+// subscribeFromDirectory subscribes to the selected directory result
+func (a *App) subscribeFromDirectory() {
+	result := a.directoryView.GetSelected()
+	if result == nil {
+		return
+	}
+	if result.FeedURL == "" {
+		a.statusMessage = "The directory doesn't list a feed for " + result.Title
+		return
+	}
+	if p := a.subscriptions.FindByURL(result.FeedURL); p != nil {
+		a.statusMessage = "Already subscribed to: " + p.Title
+		return
+	}
+	go a.addPodcast(result.FeedURL)
+}
@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/csams/podcast-tui/internal/directory"
+	"github.com/csams/podcast-tui/internal/markdown"
+	"github.com/csams/podcast-tui/internal/models"
+	"github.com/gdamore/tcell/v2"
+)
+
+// DirectoryTableRow adapts a directory search result to the TableRow interface
+type DirectoryTableRow struct {
+	result     *directory.Result
+	subscribed bool
+}
+
+func (r *DirectoryTableRow) GetCell(columnIndex int) string {
+	switch columnIndex {
+	case 0: // Subscribed
+		if r.subscribed {
+			return "✔"
+		}
+		return ""
+	case 1:
+		return r.result.Title
+	case 2:
+		return r.result.Author
+	case 3:
+		if r.result.EpisodeCount > 0 {
+			return fmt.Sprintf("%d", r.result.EpisodeCount)
+		}
+		return "—"
+	default:
+		return ""
+	}
+}
+
+func (r *DirectoryTableRow) GetCellStyle(columnIndex int, selected bool) *tcell.Style {
+	var style tcell.Style
+	switch {
+	case columnIndex == 0 && r.subscribed:
+		style = tcell.StyleDefault.Foreground(ColorSuccess)
+	case r.result.FeedURL == "":
+		// Can't be subscribed to
+		style = tcell.StyleDefault.Foreground(ColorDimmed)
+	default:
+		return nil
+	}
+	if selected {
+		style = style.Background(ColorSelection)
+	}
+	return &style
+}
+
+func (r *DirectoryTableRow) GetHighlightPositions(columnIndex int) []int {
+	return nil
+}
+
+// DirectoryView shows podcast directory search results
+type DirectoryView struct {
+	table            *Table
+	providerName     string
+	query            string
+	results          []directory.Result
+	subscriptions    *models.Subscriptions
+	converter        *markdown.MarkdownConverter
+	descScrollOffset int
+}
+
+func NewDirectoryView() *DirectoryView {
+	v := &DirectoryView{
+		table:     NewTable(),
+		converter: markdown.NewMarkdownConverter(),
+	}
+
+	v.table.SetColumns([]TableColumn{
+		{Title: "", Width: 2, Align: AlignLeft},                            // Subscribed
+		{Title: "Title", MinWidth: 20, FlexWeight: 0.6, Align: AlignLeft},  // Title
+		{Title: "Author", MinWidth: 15, FlexWeight: 0.4, Align: AlignLeft}, // Author
+		{Title: "Episodes", Width: 8, Align: AlignRight},                   // Count
+	})
+
+	return v
+}
+
+func (v *DirectoryView) SetSubscriptions(subs *models.Subscriptions) {
+	v.subscriptions = subs
+	v.updateTableRows()
+}
+
+// SetResults shows the results of searching providerName for query
+func (v *DirectoryView) SetResults(providerName, query string, results []directory.Result) {
+	v.providerName = providerName
+	v.query = query
+	v.results = results
+	v.descScrollOffset = 0
+	v.updateTableRows()
+	v.table.SelectFirst()
+}
+
+// updateTableRows rebuilds the rows, e.g. after subscribing to a result
+func (v *DirectoryView) updateTableRows() {
+	rows := make([]TableRow, len(v.results))
+	for i := range v.results {
+		result := &v.results[i]
+		rows[i] = &DirectoryTableRow{
+			result:     result,
+			subscribed: v.subscriptions != nil && result.FeedURL != "" && v.subscriptions.FindByURL(result.FeedURL) != nil,
+		}
+	}
+	v.table.SetRows(rows)
+}
+
+func (v *DirectoryView) GetSelected() *directory.Result {
+	if row, ok := v.table.GetSelectedRow().(*DirectoryTableRow); ok {
+		return row.result
+	}
+	return nil
+}
+
+func (v *DirectoryView) Draw(s tcell.Screen) {
+	w, h := s.Size()
+
+	// Calculate space allocation
+	descriptionHeight := 12
+	listHeight := h - descriptionHeight
+	if listHeight < 5 {
+		listHeight = h - 2
+		descriptionHeight = 2
+	}
+
+	headerText := fmt.Sprintf("Directory (%s) - %q: %d results", v.providerName, v.query, len(v.results))
+	drawText(s, 0, 0, tcell.StyleDefault.Bold(true), headerText)
+	for x := 0; x < w; x++ {
+		s.SetContent(x, 1, '─', nil, tcell.StyleDefault)
+	}
+
+	if len(v.results) == 0 {
+		emptyStyle := tcell.StyleDefault.Foreground(ColorDimmed)
+		drawText(s, 2, 3, emptyStyle, "No podcasts found")
+		drawText(s, 2, 5, emptyStyle, "Try ':directory <other terms>', or Esc to go back")
+		return
+	}
+
+	v.table.SetPosition(0, 2)
+	v.table.SetSize(w, listHeight-2)
+	v.table.Draw(s)
+
+	// Show scroll indicator
+	if first, last, total := v.table.GetScrollInfo(); total > last-first+1 {
+		scrollStyle := tcell.StyleDefault.Foreground(ColorDimmed)
+		drawText(s, len(headerText)+2, 0, scrollStyle, fmt.Sprintf("[%d-%d/%d]", first, last, total))
+	}
+
+	if descriptionHeight > 2 {
+		v.drawDescriptionWindow(s, listHeight, w, descriptionHeight)
+	}
+}
+
+// descriptionLines returns the preview of a result: its details and description
+func (v *DirectoryView) descriptionLines(result *directory.Result, width int) []string {
+	var lines []string
+	if result.Author != "" {
+		lines = append(lines, "Author: "+result.Author)
+	}
+	if result.Genre != "" {
+		lines = append(lines, "Genre:  "+result.Genre)
+	}
+	if result.FeedURL != "" {
+		lines = append(lines, "Feed:   "+result.FeedURL)
+	} else {
+		lines = append(lines, "Feed:   not listed by the directory; can't subscribe")
+	}
+	lines = append(lines, "")
+
+	description := strings.TrimSpace(v.converter.Convert(result.Description).Text)
+	if description == "" {
+		description = "No description available"
+	}
+	for _, paragraph := range splitPreservingNewlines(description) {
+		lines = append(lines, wrapText(paragraph, width)...)
+	}
+	return lines
+}
+
+func (v *DirectoryView) drawDescriptionWindow(s tcell.Screen, startY, width, height int) {
+	separatorStyle := tcell.StyleDefault.Foreground(ColorFgGutter)
+	for x := 0; x < width; x++ {
+		s.SetContent(x, startY, '─', nil, separatorStyle)
+	}
+
+	result := v.GetSelected()
+	if result == nil {
+		return
+	}
+	drawText(s, 0, startY+1, tcell.StyleDefault.Bold(true), result.Title)
+
+	contentWidth := width - 2
+	lines := v.descriptionLines(result, contentWidth)
+
+	maxLines := height - 3
+	maxScrollOffset := len(lines) - maxLines
+	if maxScrollOffset < 0 {
+		maxScrollOffset = 0
+	}
+	if v.descScrollOffset > maxScrollOffset {
+		v.descScrollOffset = maxScrollOffset
+	}
+
+	for i := 0; i < maxLines && i+v.descScrollOffset < len(lines); i++ {
+		line := []rune(lines[i+v.descScrollOffset])
+		if len(line) > contentWidth {
+			line = line[:contentWidth]
+		}
+		drawText(s, 1, startY+2+i, tcell.StyleDefault, string(line))
+	}
+
+	if v.descScrollOffset > 0 || len(lines) > maxLines {
+		scrollStyle := tcell.StyleDefault.Foreground(ColorDimmed)
+		scrollInfo := fmt.Sprintf("[%d-%d/%d]", v.descScrollOffset+1,
+			min(v.descScrollOffset+maxLines, len(lines)), len(lines))
+		drawText(s, width-len(scrollInfo)-2, startY+1, scrollStyle, scrollInfo)
+	}
+}
+
+func (v *DirectoryView) HandleKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		// Alt+j and Alt+k scroll the description
+		if ev.Modifiers()&tcell.ModAlt != 0 {
+			switch ev.Rune() {
+			case 'j':
+				v.descScrollOffset++
+				return true
+			case 'k':
+				if v.descScrollOffset > 0 {
+					v.descScrollOffset--
+				}
+				return true
+			}
+		}
+
+		switch ev.Rune() {
+		case 'j':
+			if v.table.SelectNext() {
+				v.descScrollOffset = 0
+				return true
+			}
+		case 'k':
+			if v.table.SelectPrevious() {
+				v.descScrollOffset = 0
+				return true
+			}
+		case 'g':
+			v.table.SelectFirst()
+			v.descScrollOffset = 0
+			return true
+		case 'G':
+			v.table.SelectLast()
+			v.descScrollOffset = 0
+			return true
+		}
+	}
+	return false
+}
+
+func (v *DirectoryView) HandlePageDown() bool {
+	if v.table.PageDown() {
+		v.descScrollOffset = 0
+		return true
+	}
+	return false
+}
+
+func (v *DirectoryView) HandlePageUp() bool {
+	if v.table.PageUp() {
+		v.descScrollOffset = 0
+		return true
+	}
+	return false
+}
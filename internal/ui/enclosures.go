@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// setMediaPreference handles ":media [audio|video|<type>|any] [high|low|default]",
+// choosing which alternative enclosure of the selected podcast's episodes is
+// played and downloaded. Without arguments it shows the current preference.
+func (a *App) setMediaPreference(args []string) {
+	podcast := a.commandTarget()
+	if podcast == nil {
+		a.statusMessage = "Select a podcast first"
+		return
+	}
+	if len(args) == 0 {
+		a.statusMessage = fmt.Sprintf("%s: %s", podcast.Title, describeMediaPreference(podcast))
+		if episode := a.selectedEpisode(); episode != nil && len(episode.Enclosures) > 1 {
+			a.statusMessage += " | " + describeEnclosures(episode)
+		}
+		return
+	}
+
+	for _, arg := range args {
+		switch arg = strings.ToLower(arg); arg {
+		case models.QualityHigh, models.QualityLow:
+			podcast.PreferredQuality = arg
+		case "default":
+			podcast.PreferredQuality = models.QualityDefault
+		case "any":
+			podcast.PreferredType = ""
+		default:
+			if arg != "audio" && arg != "video" && !strings.Contains(arg, "/") {
+				a.statusMessage = "Usage: media [audio|video|<mime-type>|any] [high|low|default]"
+				return
+			}
+			podcast.PreferredType = arg
+		}
+	}
+
+	podcast.ApplyEnclosurePreference()
+	if err := a.subscriptions.Save(); err != nil {
+		log.Printf("Failed to save media preference: %v", err)
+	}
+	a.statusMessage = fmt.Sprintf("%s: %s", podcast.Title, describeMediaPreference(podcast))
+}
+
+// selectedEpisode returns the episode under the cursor in the episode list, or nil
+func (a *App) selectedEpisode() *models.Episode {
+	if a.currentView == a.episodes {
+		return a.episodes.GetSelected()
+	}
+	return nil
+}
+
+func describeMediaPreference(podcast *models.Podcast) string {
+	mediaType := podcast.PreferredType
+	if mediaType == "" {
+		mediaType = "any type"
+	}
+	quality := podcast.PreferredQuality
+	if quality == models.QualityDefault {
+		quality = "feed's choice"
+	}
+	return fmt.Sprintf("prefers %s, %s quality", mediaType, quality)
+}
+
+// describeEnclosures lists an episode's alternatives, marking the one in use
+func describeEnclosures(episode *models.Episode) string {
+	labels := make([]string, 0, len(episode.Enclosures))
+	for _, enclosure := range episode.Enclosures {
+		label := enclosure.Label()
+		if enclosure.URL == episode.URL {
+			label = "*" + label
+		}
+		labels = append(labels, label)
+	}
+	return fmt.Sprintf("%d media options: %s", len(labels), strings.Join(labels, ", "))
+}
@@ -19,7 +19,7 @@ type EpisodeTableRow struct {
 	matchResult     *EpisodeMatchResult
 	downloadManager *download.Manager
 	currentEpisode  *models.Episode
-	player          *player.Player
+	player          player.Backend
 	podcastTitle    string
 	subscriptions   *models.Subscriptions
 }
@@ -71,6 +71,10 @@ func (r *EpisodeTableRow) GetCellStyle(columnIndex int, selected bool) *tcell.St
 			}
 		}
 	}
+	if r.episode.TextOnly && !selected {
+		style := tcell.StyleDefault.Foreground(ColorDimmed)
+		return &style
+	}
 	return nil
 }
 
@@ -138,6 +142,11 @@ func (r *EpisodeTableRow) getDownloadIndicator() string {
 		}
 	}
 
+	// Items without media can be read but not played
+	if r.episode.TextOnly {
+		indicators = append(indicators, "≡")
+	}
+
 	// Check for notes
 	if r.noteExists() {
 		indicators = append(indicators, "✎")
@@ -194,7 +203,7 @@ type EpisodeListView struct {
 	currentPodcast   *models.Podcast
 	downloadManager  *download.Manager
 	currentEpisode   *models.Episode
-	player           *player.Player
+	player           player.Backend
 	searchState      *SearchState
 	descScrollOffset int
 	subscriptions    *models.Subscriptions
@@ -241,7 +250,7 @@ func (v *EpisodeListView) SetCurrentEpisode(episode *models.Episode) {
 	v.updateTableRows()
 }
 
-func (v *EpisodeListView) SetPlayer(p *player.Player) {
+func (v *EpisodeListView) SetPlayer(p player.Backend) {
 	v.player = p
 }
 
@@ -566,6 +575,17 @@ func (v *EpisodeListView) drawDescriptionWindow(s tcell.Screen, startY, width, h
 	
 	headerStyle := tcell.StyleDefault.Bold(true)
 	drawText(s, 0, startY+1, headerStyle, "Description")
+	if selectedEpisode != nil {
+		mediaInfo := ""
+		if selectedEpisode.TextOnly {
+			mediaInfo = "text only, no audio"
+		} else if len(selectedEpisode.Enclosures) > 1 {
+			mediaInfo = describeEnclosures(selectedEpisode)
+		}
+		if mediaInfo != "" {
+			drawText(s, len("Description")+2, startY+1, tcell.StyleDefault.Foreground(ColorDimmed), mediaInfo)
+		}
+	}
 
 	if description != "" {
 		var highlightPositions []int
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/feed"
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// feedURLs returns every URL credentials for the podcast may be stored under,
+// current URL first
+func feedURLs(podcast *models.Podcast) []string {
+	urls := []string{podcast.URL}
+	for i := len(podcast.PreviousURLs) - 1; i >= 0; i-- {
+		urls = append(urls, podcast.PreviousURLs[i])
+	}
+	return urls
+}
+
+// feedOptions returns the options for fetching the podcast's feed from feedURL
+func (a *App) feedOptions(podcast *models.Podcast, feedURL string) feed.Options {
+	return feed.Options{
+		IdentityURL: podcast.EpisodeIDURL(),
+		Credential:  a.credentials.Lookup(feedURL, feedURLs(podcast)...),
+		MaxBytes:    a.settings.MaxFeedBytes(),
+		UserAgent:   a.settings.HTTPUserAgent(),
+	}
+}
+
+// enclosureCredential returns the credential for downloading an episode. Credentials
+// are only sent to the feed's host or hosts explicitly allowed for it, checked
+// against the URL actually fetched, without any tracking prefixes privacy mode removes.
+func (a *App) enclosureCredential(episode *models.Episode) *credentials.Credential {
+	if a.subscriptions == nil {
+		return nil
+	}
+	podcast := a.subscriptions.GetPodcastForEpisode(episode.ID)
+	if podcast == nil {
+		return nil
+	}
+	return a.credentials.Lookup(a.untrackedURL(episode.URL), feedURLs(podcast)...)
+}
+
+// commandTarget returns the podcast a command such as auth or history applies to
+func (a *App) commandTarget() *models.Podcast {
+	if a.currentView == a.podcasts {
+		return a.podcasts.GetSelected()
+	} else if a.currentView == a.episodes {
+		return a.episodes.GetCurrentPodcast()
+	}
+	return nil
+}
+
+// editFeedCredential applies edit to a copy of the podcast's credential and stores
+// it under the current feed URL, replacing any it was stored under before the
+// feed moved
+func (a *App) editFeedCredential(podcast *models.Podcast, edit func(cred *credentials.Credential)) error {
+	cred := &credentials.Credential{Headers: make(map[string]string)}
+	if existing := a.credentials.Lookup(podcast.URL, feedURLs(podcast)...); existing != nil {
+		cred.Username = existing.Username
+		cred.Password = existing.Password
+		cred.AllowedHosts = append(cred.AllowedHosts, existing.AllowedHosts...)
+		for name, value := range existing.Headers {
+			cred.Headers[name] = value
+		}
+	}
+
+	edit(cred)
+	for _, url := range feedURLs(podcast)[1:] {
+		if existing := a.credentials.Get(url); existing != nil && existing.Allows(podcast.URL) {
+			a.credentials.Remove(url)
+		}
+	}
+	a.credentials.Set(podcast.URL, cred)
+	return a.credentials.Save()
+}
+
+// moveFeedCredential keeps the credential stored under the URL a feed moved from
+// working at its new URL, which may be on another host
+func (a *App) moveFeedCredential(oldURL, newURL string) {
+	if !a.credentials.Move(oldURL, newURL) {
+		return
+	}
+	if err := a.credentials.Save(); err != nil {
+		log.Printf("Failed to save credentials: %v", err)
+	}
+}
+
+// setFeedAuth handles ":auth <user> <password>" and ":auth clear" for the selected podcast
+func (a *App) setFeedAuth(args []string) {
+	podcast := a.commandTarget()
+	if podcast == nil {
+		a.statusMessage = "Select a podcast first"
+		return
+	}
+
+	var err error
+	switch {
+	case len(args) == 1 && args[0] == "clear":
+		err = a.editFeedCredential(podcast, func(cred *credentials.Credential) {
+			cred.Username = ""
+			cred.Password = ""
+		})
+		a.statusMessage = "Cleared login for " + podcast.Title
+	case len(args) == 2:
+		err = a.editFeedCredential(podcast, func(cred *credentials.Credential) {
+			cred.Username = args[0]
+			cred.Password = args[1]
+		})
+		a.statusMessage = fmt.Sprintf("Saved login for %s (r to refresh)", podcast.Title)
+	default:
+		a.statusMessage = "Usage: auth <username> <password> | auth clear"
+		return
+	}
+
+	if err != nil {
+		log.Printf("Failed to save credentials: %v", err)
+		a.statusMessage = "Error saving credentials: " + err.Error()
+	}
+}
+
+// setFeedHeader handles ":header <Name>: <value>", ":header <Name>" (removes it) and
+// ":header clear" for the selected podcast
+func (a *App) setFeedHeader(arg string) {
+	podcast := a.commandTarget()
+	if podcast == nil {
+		a.statusMessage = "Select a podcast first"
+		return
+	}
+	if arg == "" {
+		a.statusMessage = "Usage: header <Name>: <value> | header <Name> | header clear"
+		return
+	}
+
+	var name, value string
+	if i := strings.IndexAny(arg, ": "); i >= 0 {
+		name = strings.TrimSpace(arg[:i])
+		value = strings.TrimSpace(arg[i+1:])
+	} else {
+		name = arg
+	}
+
+	err := a.editFeedCredential(podcast, func(cred *credentials.Credential) {
+		switch {
+		case arg == "clear":
+			cred.Headers = nil
+		case value == "":
+			delete(cred.Headers, name)
+		default:
+			cred.Headers[name] = value
+		}
+	})
+
+	switch {
+	case arg == "clear":
+		a.statusMessage = "Cleared headers for " + podcast.Title
+	case value == "":
+		a.statusMessage = fmt.Sprintf("Removed header %s for %s", name, podcast.Title)
+	default:
+		a.statusMessage = fmt.Sprintf("Saved header %s for %s (r to refresh)", name, podcast.Title)
+	}
+
+	if err != nil {
+		log.Printf("Failed to save credentials: %v", err)
+		a.statusMessage = "Error saving credentials: " + err.Error()
+	}
+}
+
+// forgetFeedCredentials removes any stored credentials for a deleted podcast
+func (a *App) forgetFeedCredentials(podcast *models.Podcast) {
+	removed := false
+	for _, url := range feedURLs(podcast) {
+		if a.credentials.Get(url) != nil {
+			a.credentials.Remove(url)
+			removed = true
+		}
+	}
+	if removed {
+		if err := a.credentials.Save(); err != nil {
+			log.Printf("Failed to save credentials: %v", err)
+		}
+	}
+}
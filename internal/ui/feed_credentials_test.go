@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/feed"
+)
+
+func TestApp_EnclosureCredentialUntracked(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 1, time.Hour)
+	podcast := app.subscriptions.Podcasts[0]
+	app.credentials.Set(podcast.URL, &credentials.Credential{Username: "listener", Password: "secret"})
+
+	// Behind a tracking prefix the request goes to the tracker, which must not
+	// get the credential, unless privacy mode removes the prefix
+	episodes[0].URL = "https://dts.podtrac.com/redirect.mp3/example.com/1.mp3"
+	if cred := app.enclosureCredential(episodes[0]); cred != nil {
+		t.Errorf("Expected no credential for the tracker's host")
+	}
+	app.settings.PrivacyMode = true
+	if cred := app.enclosureCredential(episodes[0]); cred == nil || cred.Username != "listener" {
+		t.Errorf("Expected the feed's credential for the untracked URL, got %+v", cred)
+	}
+
+	// Other hosts still don't get it
+	episodes[0].URL = "https://tracker.example.net/1.mp3"
+	if cred := app.enclosureCredential(episodes[0]); cred != nil {
+		t.Errorf("Expected no credential for another host")
+	}
+}
+
+func TestApp_FeedCredentialFollowsMove(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 1, time.Hour)
+	podcast := app.subscriptions.Podcasts[0]
+	oldURL := podcast.URL
+	app.credentials.Set(oldURL, &credentials.Credential{Username: "listener", Password: "secret"})
+
+	// A permanent redirect to another host takes the credential along, and the
+	// old host, still serving the enclosures, keeps getting it
+	newURL := "https://feeds.example.net/feed.xml"
+	if !app.applyFeedMove(podcast, &feed.Result{MovedTo: newURL}) {
+		t.Fatal("Expected the feed to move")
+	}
+	if cred := app.feedOptions(podcast, newURL).Credential; cred == nil || cred.Username != "listener" {
+		t.Errorf("Expected the credential sent to the moved feed, got %+v", cred)
+	}
+	if cred := app.enclosureCredential(episodes[0]); cred == nil {
+		t.Error("Expected the credential sent to the old host's enclosures")
+	}
+}
+
+func TestApp_EditFeedCredentialAfterMove(t *testing.T) {
+	app, fake := newTestApp(t)
+	addTestPodcast(app, fake, 1, time.Hour)
+	podcast := app.subscriptions.Podcasts[0]
+	oldURL := podcast.URL
+	app.credentials.Set(oldURL, &credentials.Credential{Username: "listener", Password: "secret"})
+	podcast.MoveTo("https://example.com/moved.xml")
+	app.podcasts.SetSubscriptions(app.subscriptions)
+	app.currentView = app.podcasts
+
+	app.setFeedHeader("X-Token: abc")
+	cred := app.credentials.Get(podcast.URL)
+	if cred == nil || cred.Username != "listener" || cred.Headers["X-Token"] != "abc" {
+		t.Errorf("Expected the login kept alongside the new header, got %+v", cred)
+	}
+	if app.credentials.Get(oldURL) != nil {
+		t.Error("Expected the entry under the old URL replaced")
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// offerMerge asks whether a newly fetched feed that duplicates a subscription
+// should be merged into it rather than subscribed to separately
+func (a *App) offerMerge(existing, duplicate *models.Podcast, reason string, cred *credentials.Credential) {
+	message := fmt.Sprintf("'%s' appears to be '%s', which you already subscribe to (%s). Merge it into the existing subscription?",
+		duplicate.Title, existing.Title, reason)
+	a.confirmDialog.Show("Duplicate Podcast", message,
+		func() {
+			a.mergeDuplicate(existing, duplicate, cred)
+		},
+		func() {
+			a.statusMessage = "Not subscribed: duplicate of " + existing.Title
+		})
+	a.draw()
+}
+
+// mergeDuplicate folds a duplicate feed into the existing subscription, which keeps
+// its episodes and playback state. The duplicate's URLs are remembered so adding
+// it again is recognised.
+func (a *App) mergeDuplicate(existing, duplicate *models.Podcast, cred *credentials.Credential) {
+	existing.AddAlias(duplicate.URL)
+	for _, url := range duplicate.PreviousURLs {
+		existing.AddAlias(url)
+	}
+
+	// A login given for the duplicate is kept if the subscription has none
+	if cred != nil && a.credentials.Lookup(existing.URL, feedURLs(existing)...) == nil {
+		a.credentials.Set(duplicate.URL, cred)
+		if err := a.credentials.Save(); err != nil {
+			log.Printf("Failed to save credentials: %v", err)
+		}
+	}
+
+	if err := a.subscriptions.Save(); err != nil {
+		a.statusMessage = "Error saving: " + err.Error()
+		log.Printf("Failed to save subscriptions: %v", err)
+		return
+	}
+
+	log.Printf("Merged %s into '%s'", credentials.RedactURL(duplicate.URL), existing.Title)
+	a.podcasts.SetSubscriptions(a.subscriptions)
+	a.directoryView.SetSubscriptions(a.subscriptions)
+	a.statusMessage = "Merged into existing subscription: " + existing.Title
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/models"
+	"github.com/gdamore/tcell/v2"
+)
+
+// FeedHealthDialog shows the refresh history of a podcast feed
+type FeedHealthDialog struct {
+	visible      bool
+	podcast      *models.Podcast
+	onRetry      func()
+	scrollOffset int
+}
+
+func NewFeedHealthDialog() *FeedHealthDialog {
+	return &FeedHealthDialog{
+		visible: false,
+	}
+}
+
+func (d *FeedHealthDialog) Show(podcast *models.Podcast, onRetry func()) {
+	d.visible = true
+	d.podcast = podcast
+	d.onRetry = onRetry
+	d.scrollOffset = 0
+}
+
+func (d *FeedHealthDialog) Hide() {
+	d.visible = false
+	d.podcast = nil
+	d.onRetry = nil
+}
+
+func (d *FeedHealthDialog) IsVisible() bool {
+	return d.visible
+}
+
+// getContent builds the dialog lines from the podcast's current health
+func (d *FeedHealthDialog) getContent() []string {
+	p := d.podcast
+	lines := []string{
+		p.Title,
+		p.URL,
+		"",
+	}
+
+	status := "OK"
+	if p.Retired {
+		status = "Retired (feed is gone; no longer refreshed)"
+	} else if p.IsBroken() {
+		status = "Broken"
+	} else if p.HasRefreshErrors() {
+		status = "Failing"
+	}
+	lines = append(lines, fmt.Sprintf("Status:               %s", status))
+	lines = append(lines, fmt.Sprintf("Last success:         %s", formatHealthTime(p.LastSuccess)))
+	if p.LastHTTPStatus != 0 {
+		lines = append(lines, fmt.Sprintf("Last HTTP status:     %d %s", p.LastHTTPStatus, http.StatusText(p.LastHTTPStatus)))
+	}
+	lines = append(lines, fmt.Sprintf("Consecutive failures: %d", p.ConsecutiveFailures))
+	if p.Retired {
+		lines = append(lines, fmt.Sprintf("Retired:              %s", formatHealthTime(p.RetiredAt)))
+	} else {
+		lines = append(lines, fmt.Sprintf("Next refresh:         %s", formatHealthTime(p.NextRefresh)))
+	}
+	for _, previous := range p.PreviousURLs {
+		lines = append(lines, fmt.Sprintf("Moved from:           %s", previous))
+	}
+	if issues := p.ParseIssues; issues.Total() > 0 {
+		lines = append(lines, fmt.Sprintf("Unparsed dates:       %s", formatParseIssue(issues.Dates, issues.DateSample)))
+		lines = append(lines, fmt.Sprintf("Unparsed durations:   %s", formatParseIssue(issues.Durations, issues.DurationSample)))
+	}
+	if p.LastError != "" {
+		lines = append(lines, "", "Last error:")
+		lines = append(lines, "  "+p.LastError)
+	}
+
+	lines = append(lines, "", "Recent errors:")
+	if len(p.RecentErrors) == 0 {
+		lines = append(lines, "  None")
+	}
+	for _, feedErr := range p.RecentErrors {
+		lines = append(lines, fmt.Sprintf("  %s  %s", feedErr.Time.Local().Format("2006-01-02 15:04"), feedErr.Message))
+	}
+
+	return lines
+}
+
+// formatParseIssue formats a count of unparseable feed values with an example
+func formatParseIssue(count int, sample string) string {
+	if count == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d (e.g. %q)", count, sample)
+}
+
+// formatHealthTime formats a timestamp for the health dialog
+func formatHealthTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func (d *FeedHealthDialog) Draw(s tcell.Screen) {
+	if !d.visible || d.podcast == nil {
+		return
+	}
+
+	w, screenHeight := s.Size()
+	lines := d.getContent()
+
+	// Size the dialog to its content, within the screen
+	dialogWidth := 40
+	for _, line := range lines {
+		if len(line)+4 > dialogWidth {
+			dialogWidth = len(line) + 4
+		}
+	}
+	if dialogWidth > w-4 {
+		dialogWidth = w - 4
+	}
+	dialogHeight := len(lines) + 6 // Content + borders + title + padding + actions
+	if dialogHeight > screenHeight-4 {
+		dialogHeight = screenHeight - 4
+	}
+	if dialogWidth < 10 || dialogHeight < 7 {
+		return
+	}
+
+	startX := (w - dialogWidth) / 2
+	startY := (screenHeight - dialogHeight) / 2
+
+	// Draw dialog background
+	dialogStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorFg)
+	for y := startY; y < startY+dialogHeight; y++ {
+		for x := startX; x < startX+dialogWidth; x++ {
+			s.SetContent(x, y, ' ', nil, dialogStyle)
+		}
+	}
+
+	// Draw border
+	borderStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorBorder)
+	for x := startX; x < startX+dialogWidth; x++ {
+		if x == startX {
+			s.SetContent(x, startY, '┌', nil, borderStyle)
+			s.SetContent(x, startY+dialogHeight-1, '└', nil, borderStyle)
+		} else if x == startX+dialogWidth-1 {
+			s.SetContent(x, startY, '┐', nil, borderStyle)
+			s.SetContent(x, startY+dialogHeight-1, '┘', nil, borderStyle)
+		} else {
+			s.SetContent(x, startY, '─', nil, borderStyle)
+			s.SetContent(x, startY+dialogHeight-1, '─', nil, borderStyle)
+		}
+	}
+	for y := startY + 1; y < startY+dialogHeight-1; y++ {
+		s.SetContent(startX, y, '│', nil, borderStyle)
+		s.SetContent(startX+dialogWidth-1, y, '│', nil, borderStyle)
+	}
+
+	// Title, colored by health
+	titleColor := ColorSuccess
+	if d.podcast.Retired {
+		titleColor = ColorDimmed
+	} else if d.podcast.IsBroken() {
+		titleColor = ColorError
+	} else if d.podcast.HasRefreshErrors() {
+		titleColor = ColorYellow
+	}
+	titleStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(titleColor).Bold(true)
+	title := "Feed Health"
+	drawText(s, startX+(dialogWidth-len(title))/2, startY+1, titleStyle, title)
+
+	// Content, scrollable when it doesn't fit
+	visibleLines := dialogHeight - 6
+	maxScroll := len(lines) - visibleLines
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if d.scrollOffset > maxScroll {
+		d.scrollOffset = maxScroll
+	}
+
+	contentWidth := dialogWidth - 4
+	for i := 0; i < visibleLines && i+d.scrollOffset < len(lines); i++ {
+		line := []rune(lines[i+d.scrollOffset])
+		if len(line) > contentWidth {
+			line = line[:contentWidth]
+		}
+		drawText(s, startX+2, startY+3+i, dialogStyle, string(line))
+	}
+
+	// Actions
+	actionStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorFg).Bold(true)
+	actions := "[r] Retry now   [Esc] Close"
+	drawText(s, startX+(dialogWidth-len(actions))/2, startY+dialogHeight-2, actionStyle, actions)
+}
+
+func (d *FeedHealthDialog) HandleKey(ev *tcell.EventKey) bool {
+	if !d.visible {
+		return false
+	}
+
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		d.Hide()
+		return true
+	case tcell.KeyUp:
+		if d.scrollOffset > 0 {
+			d.scrollOffset--
+		}
+		return true
+	case tcell.KeyDown:
+		d.scrollOffset++
+		return true
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'r':
+			onRetry := d.onRetry
+			d.Hide()
+			if onRetry != nil {
+				onRetry()
+			}
+			return true
+		case 'q', 'H':
+			d.Hide()
+			return true
+		case 'j':
+			d.scrollOffset++
+			return true
+		case 'k':
+			if d.scrollOffset > 0 {
+				d.scrollOffset--
+			}
+			return true
+		}
+	}
+
+	return true // Consume all other keys when visible
+}
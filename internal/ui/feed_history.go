@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+	"github.com/csams/podcast-tui/internal/feed"
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// startHistoryFetch handles ":history", fetching every page of the selected
+// podcast's feed
+func (a *App) startHistoryFetch() {
+	podcast := a.commandTarget()
+	if podcast == nil {
+		a.statusMessage = "Select a podcast first"
+		return
+	}
+	if source, err := feed.SourceFor(podcast.URL); err == nil && !source.Capabilities().History {
+		a.statusMessage = fmt.Sprintf("%s is a %s, which has no history to fetch", podcast.Title, source.Name())
+		return
+	}
+	a.statusMessage = fmt.Sprintf("Fetching full history of %s...", podcast.Title)
+	go a.fetchPodcastHistory(podcast)
+}
+
+// fetchPodcastHistory follows the pagination links of the podcast's feed and
+// merges every page into it
+func (a *App) fetchPodcastHistory(podcast *models.Podcast) {
+	feedURL := podcast.URL
+	a.refreshMutex.Lock()
+	if a.activeRefreshes[feedURL] {
+		a.refreshMutex.Unlock()
+		a.statusMessage = fmt.Sprintf("%s is already refreshing", podcast.Title)
+		a.draw()
+		return
+	}
+	a.activeRefreshes[feedURL] = true
+	a.refreshMutex.Unlock()
+
+	a.refreshSemaphore <- struct{}{}
+	defer func() {
+		<-a.refreshSemaphore
+		a.refreshMutex.Lock()
+		delete(a.activeRefreshes, feedURL)
+		a.refreshMutex.Unlock()
+	}()
+
+	result, err := feed.FetchHistory(feedURL, a.feedOptions(podcast, feedURL), a.settings.HistoryLimits())
+	if err != nil {
+		log.Printf("Failed to fetch history of '%s' from %s: %v", podcast.Title, credentials.RedactURL(feedURL), err)
+		a.recordFeedFailure(podcast, err)
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save subscriptions: %v", err)
+		}
+		a.statusMessage = fmt.Sprintf("Failed to fetch history of %s: %v", podcast.Title, err)
+		a.podcasts.SetSubscriptions(a.subscriptions)
+		a.draw()
+		return
+	}
+
+	added := a.mergePodcastData(podcast, result.Podcast)
+	podcast.RecordRefreshSuccess(time.Now(), http.StatusOK)
+	a.applyFeedMove(podcast, result)
+
+	if err := a.subscriptions.Save(); err != nil {
+		log.Printf("Failed to save subscriptions: %v", err)
+	}
+
+	a.podcasts.SetSubscriptions(a.subscriptions)
+	if a.episodes.GetCurrentPodcast() == podcast {
+		a.episodes.SetPodcast(podcast)
+	}
+
+	switch {
+	case result.Pages == 1 && !podcast.Paginated:
+		a.statusMessage = fmt.Sprintf("%s has no older pages; %d new episodes", podcast.Title, len(added))
+	case result.NextPage != "":
+		a.statusMessage = fmt.Sprintf("Fetched %d pages of %s (%d new episodes); older pages remain, see the log",
+			result.Pages, podcast.Title, len(added))
+	default:
+		a.statusMessage = fmt.Sprintf("Fetched full history of %s: %d pages, %d new episodes",
+			podcast.Title, result.Pages, len(added))
+	}
+	a.draw()
+}
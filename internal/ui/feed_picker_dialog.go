@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/csams/podcast-tui/internal/feed"
+	"github.com/gdamore/tcell/v2"
+)
+
+// FeedPickerDialog lets the user choose between the feeds a web page advertises
+type FeedPickerDialog struct {
+	visible      bool
+	pageURL      string
+	feeds        []feed.DiscoveredFeed
+	selected     int
+	scrollOffset int
+	onSelect     func(feed.DiscoveredFeed)
+}
+
+func NewFeedPickerDialog() *FeedPickerDialog {
+	return &FeedPickerDialog{
+		visible: false,
+	}
+}
+
+func (d *FeedPickerDialog) Show(pageURL string, feeds []feed.DiscoveredFeed, onSelect func(feed.DiscoveredFeed)) {
+	d.visible = true
+	d.pageURL = pageURL
+	d.feeds = feeds
+	d.selected = 0
+	d.scrollOffset = 0
+	d.onSelect = onSelect
+}
+
+func (d *FeedPickerDialog) Hide() {
+	d.visible = false
+	d.feeds = nil
+	d.onSelect = nil
+}
+
+func (d *FeedPickerDialog) IsVisible() bool {
+	return d.visible
+}
+
+// feedLabel describes a discovered feed in the list
+func feedLabel(f feed.DiscoveredFeed) string {
+	kind := "RSS"
+	if f.Type == "application/atom+xml" {
+		kind = "Atom"
+	}
+	title := f.Title
+	if title == "" {
+		title = f.URL
+	}
+	return fmt.Sprintf("%s (%s)", title, kind)
+}
+
+func (d *FeedPickerDialog) Draw(s tcell.Screen) {
+	if !d.visible || len(d.feeds) == 0 {
+		return
+	}
+
+	w, screenHeight := s.Size()
+
+	// Each feed takes two lines: its label and its URL
+	dialogWidth := 50
+	for _, f := range d.feeds {
+		for _, line := range []string{feedLabel(f), f.URL} {
+			if len(line)+8 > dialogWidth {
+				dialogWidth = len(line) + 8
+			}
+		}
+	}
+	if dialogWidth > w-4 {
+		dialogWidth = w - 4
+	}
+	dialogHeight := len(d.feeds)*2 + 7 // Feeds + borders + title + page + padding + actions
+	if dialogHeight > screenHeight-4 {
+		dialogHeight = screenHeight - 4
+	}
+	if dialogWidth < 20 || dialogHeight < 9 {
+		return
+	}
+
+	startX := (w - dialogWidth) / 2
+	startY := (screenHeight - dialogHeight) / 2
+
+	// Draw dialog background
+	dialogStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorFg)
+	for y := startY; y < startY+dialogHeight; y++ {
+		for x := startX; x < startX+dialogWidth; x++ {
+			s.SetContent(x, y, ' ', nil, dialogStyle)
+		}
+	}
+
+	// Draw border
+	borderStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorBorder)
+	for x := startX; x < startX+dialogWidth; x++ {
+		if x == startX {
+			s.SetContent(x, startY, '┌', nil, borderStyle)
+			s.SetContent(x, startY+dialogHeight-1, '└', nil, borderStyle)
+		} else if x == startX+dialogWidth-1 {
+			s.SetContent(x, startY, '┐', nil, borderStyle)
+			s.SetContent(x, startY+dialogHeight-1, '┘', nil, borderStyle)
+		} else {
+			s.SetContent(x, startY, '─', nil, borderStyle)
+			s.SetContent(x, startY+dialogHeight-1, '─', nil, borderStyle)
+		}
+	}
+	for y := startY + 1; y < startY+dialogHeight-1; y++ {
+		s.SetContent(startX, y, '│', nil, borderStyle)
+		s.SetContent(startX+dialogWidth-1, y, '│', nil, borderStyle)
+	}
+
+	contentWidth := dialogWidth - 4
+	truncate := func(text string) string {
+		runes := []rune(text)
+		if len(runes) > contentWidth {
+			runes = runes[:contentWidth]
+		}
+		return string(runes)
+	}
+
+	titleStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorHeader).Bold(true)
+	title := "Choose a Feed"
+	drawText(s, startX+(dialogWidth-len(title))/2, startY+1, titleStyle, title)
+	dimStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorDimmed)
+	drawText(s, startX+2, startY+2, dimStyle, truncate(d.pageURL))
+
+	// Feeds, scrolled to keep the selection visible
+	visibleFeeds := (dialogHeight - 7) / 2
+	if d.selected < d.scrollOffset {
+		d.scrollOffset = d.selected
+	} else if d.selected >= d.scrollOffset+visibleFeeds {
+		d.scrollOffset = d.selected - visibleFeeds + 1
+	}
+
+	selectedStyle := tcell.StyleDefault.Background(ColorBlue).Foreground(ColorBg)
+	for i := 0; i < visibleFeeds && i+d.scrollOffset < len(d.feeds); i++ {
+		index := i + d.scrollOffset
+		f := d.feeds[index]
+		labelStyle, urlStyle := dialogStyle, dimStyle
+		if index == d.selected {
+			labelStyle, urlStyle = selectedStyle, selectedStyle
+		}
+		y := startY + 4 + i*2
+		drawText(s, startX+2, y, labelStyle, truncate(fmt.Sprintf("%d. %s", index+1, feedLabel(f))))
+		drawText(s, startX+2, y+1, urlStyle, truncate("   "+f.URL))
+	}
+
+	// Actions
+	actionStyle := tcell.StyleDefault.Background(ColorBgHighlight).Foreground(ColorFg).Bold(true)
+	actions := "[Enter] Subscribe   [Esc] Cancel"
+	drawText(s, startX+(dialogWidth-len(actions))/2, startY+dialogHeight-2, actionStyle, actions)
+}
+
+func (d *FeedPickerDialog) HandleKey(ev *tcell.EventKey) bool {
+	if !d.visible {
+		return false
+	}
+
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		d.Hide()
+		return true
+	case tcell.KeyEnter:
+		d.choose(d.selected)
+		return true
+	case tcell.KeyUp:
+		d.move(-1)
+		return true
+	case tcell.KeyDown:
+		d.move(1)
+		return true
+	case tcell.KeyRune:
+		switch r := ev.Rune(); {
+		case r == 'q':
+			d.Hide()
+		case r == 'j':
+			d.move(1)
+		case r == 'k':
+			d.move(-1)
+		case r >= '1' && r <= '9':
+			d.choose(int(r - '1'))
+		}
+		return true
+	}
+
+	// Dialog is modal
+	return true
+}
+
+func (d *FeedPickerDialog) move(delta int) {
+	d.selected += delta
+	if d.selected < 0 {
+		d.selected = 0
+	}
+	if d.selected >= len(d.feeds) {
+		d.selected = len(d.feeds) - 1
+	}
+}
+
+// choose closes the dialog and subscribes to the feed at index
+func (d *FeedPickerDialog) choose(index int) {
+	if index < 0 || index >= len(d.feeds) {
+		return
+	}
+	chosen := d.feeds[index]
+	onSelect := d.onSelect
+	d.Hide()
+	if onSelect != nil {
+		onSelect(chosen)
+	}
+}
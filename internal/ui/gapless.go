@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/feed"
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// preloadNext hands the episode after the current one in the queue to the
+// player, which starts it the moment the current one ends, from where
+// playEpisode would. It is called whenever the current episode or the queue changes.
+// Nothing is preloaded when the sleep timer stops at the end of the episode.
+func (a *App) preloadNext() {
+	a.preloadMu.Lock()
+	defer a.preloadMu.Unlock()
+
+	var next *models.Episode
+	if current := a.currentEpisode; current != nil && !a.sleepsAtEndOfEpisode() {
+		for _, episode := range a.subscriptions.GetQueueEpisodes() {
+			if episode.ID != current.ID {
+				next = episode
+				break
+			}
+		}
+	}
+	if next != nil && !next.Playable() {
+		// Left to the queue's usual advancing, which skips it
+		next = nil
+	}
+	if next == nil && a.preloaded == nil {
+		return
+	}
+
+	url, start, outro := "", time.Duration(0), time.Duration(0)
+	if next != nil {
+		url = a.preloadURL(next)
+		start = a.startPosition(next, next.LastPlayed)
+		outro = outroFor(a.subscriptions.GetPodcastForEpisode(next.ID))
+	}
+	if err := a.player.Preload(url, start, outro); err != nil {
+		log.Printf("Failed to preload %s: %v", url, err)
+		next, url = nil, ""
+	}
+	a.preloaded, a.preloadedURL = next, url
+}
+
+// preloadURL returns where episode is played from, as playEpisode picks it
+func (a *App) preloadURL(episode *models.Episode) string {
+	if location, local := feed.PlaybackLocation(episode.URL); local {
+		return location
+	}
+	if episode.Downloaded && episode.DownloadPath != "" {
+		if _, err := os.Stat(episode.DownloadPath); err == nil {
+			return episode.DownloadPath
+		}
+	}
+	return a.untrackedURL(episode.URL)
+}
+
+// handleNextFile follows the player onto the preloaded episode when the
+// current one ended: the finished episode is marked played and leaves the
+// queue, as in handleEpisodeCompletion, and the one after is preloaded.
+func (a *App) handleNextFile(url string) {
+	a.transitionMutex.Lock()
+	defer a.transitionMutex.Unlock()
+
+	a.preloadMu.Lock()
+	next, nextURL := a.preloaded, a.preloadedURL
+	a.preloaded, a.preloadedURL = nil, ""
+	a.preloadMu.Unlock()
+
+	a.stopPositionTicker()
+	if finished := a.currentEpisode; finished != nil {
+		if episode := a.subscriptions.GetEpisodeByID(finished.ID); episode != nil {
+			episode.Played = true
+		}
+		finished.Played = true
+		a.subscriptions.RemoveFromQueue(finished.ID)
+	}
+	if err := a.subscriptions.Save(); err != nil {
+		log.Printf("Failed to save queue after finishing episode: %v", err)
+	}
+
+	if next == nil || nextURL != url {
+		// The queue changed as the player moved on; play its next episode instead
+		log.Printf("Player moved on to %s, which is not the preloaded episode", url)
+		a.currentEpisode = nil
+		if a.subscriptions.GetNextInQueue() == nil {
+			a.player.StopKeepIdle()
+		}
+		a.playNextInQueue()
+		return
+	}
+
+	log.Printf("Continuing gaplessly with next episode in queue: %s", next.Title)
+	if canonical := a.subscriptions.GetEpisodeByID(next.ID); canonical != nil {
+		next = canonical
+	}
+	a.currentEpisode = next
+	a.currentPodcast = a.subscriptions.GetPodcastForEpisode(next.ID)
+	a.episodes.SetCurrentEpisode(next)
+	a.queue.SetCurrentEpisode(next)
+	next.LastPlayed = time.Now()
+	a.completionHandled.Store(false)
+	a.applyAudioFilters()
+	a.startPositionTicker()
+
+	if a.currentView == a.queue {
+		a.queue.refresh()
+	}
+	if a.currentView == a.episodes {
+		a.episodes.updateTableRows()
+	}
+	a.statusMessage = "Playing next: " + next.Title
+	a.draw()
+
+	a.preloadNext()
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApp_GaplessQueue(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 3, 10*time.Minute)
+	episodes[1].Position = 2 * time.Minute
+
+	app.addToQueue(episodes[0])
+	app.addToQueue(episodes[1])
+	if url, start := fake.Preloaded(); url != episodes[1].URL || start != 2*time.Minute {
+		t.Fatalf("Expected the next episode preloaded at its saved position, got %q at %v", url, start)
+	}
+
+	// The preloaded episode takes over without loading it again
+	fake.Finish()
+	settle(app, fake)
+	if app.currentEpisode == nil || app.currentEpisode.ID != episodes[1].ID {
+		t.Fatalf("Expected the second episode to play")
+	}
+	if position, _ := fake.GetPosition(); position != 2*time.Minute {
+		t.Errorf("Expected to continue at 2:00, got %v", position)
+	}
+	if !episodes[0].Played || app.subscriptions.GetQueuePosition(episodes[0].ID) != 0 || len(app.subscriptions.Queue) != 1 {
+		t.Errorf("Expected the first episode played and out of the queue, got played=%v and %d entries", episodes[0].Played, len(app.subscriptions.Queue))
+	}
+	if got := fake.Loaded(); len(got) != 2 {
+		t.Errorf("Expected each episode loaded once, got %v", got)
+	}
+	if app.statusMessage != "Playing next: Episode 2" {
+		t.Errorf("Expected the status to show the next episode, got %q", app.statusMessage)
+	}
+
+	// The preloaded episode follows the queue
+	if url, _ := fake.Preloaded(); url != "" {
+		t.Errorf("Expected nothing preloaded at the end of the queue, got %q", url)
+	}
+	app.addToQueue(episodes[2])
+	if url, _ := fake.Preloaded(); url != episodes[2].URL {
+		t.Errorf("Expected the new queue entry preloaded, got %q", url)
+	}
+	app.setSleepTimer([]string{"episode"})
+	if url, _ := fake.Preloaded(); url != "" {
+		t.Errorf("Expected nothing preloaded when the sleep timer stops at the end, got %q", url)
+	}
+	app.cancelSleepTimer()
+	if url, _ := fake.Preloaded(); url != episodes[2].URL {
+		t.Errorf("Expected the preload back after cancelling the sleep timer, got %q", url)
+	}
+	app.removeFromQueue(episodes[2])
+	if url, _ := fake.Preloaded(); url != "" {
+		t.Errorf("Expected the removed entry no longer preloaded, got %q", url)
+	}
+}
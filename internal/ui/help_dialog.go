@@ -287,7 +287,7 @@ func (h *HelpDialog) getHelpContent() []string {
 		"",
 		"  Note: First episode added to empty queue starts playing automatically",
 		"  Episodes play sequentially; completed episodes are removed from queue",
-		"  Auto-advances to next episode when one completes",
+		"  Auto-advances to next episode when one completes, without a gap",
 		"",
 		"Playback Control:",
 		"  Space         Pause/resume current episode",
@@ -301,8 +301,13 @@ func (h *HelpDialog) getHelpContent() []string {
 		"  < / >         Decrease/increase playback speed",
 		"  =             Reset to normal speed (1.0x)",
 		"  Up/Down       Increase/decrease volume by 5%",
+		"  z             Sleep timer: start at 15 minutes, or add 15 minutes",
+		"  Z             Cancel the sleep timer",
 		"",
 		"  Note: Positions are saved automatically and updated in real-time",
+		"  Note: With smartRewind set, resuming after a pause goes back 3-30 seconds",
+		"  Note: The sleep timer fades out over its last 30 seconds",
+		"  Note: Audio filters in use are shown as [AF:...], or [AF] in narrow windows",
 		"",
 		"Episode Downloads:",
 		"  d             Download selected episode",
@@ -311,7 +316,13 @@ func (h *HelpDialog) getHelpContent() []string {
 		"Podcast Management:",
 		"  a             Add new podcast (enters command mode)",
 		"  x             Delete selected podcast (with confirmation)",
-		"  r             Refresh feeds (all in podcast list, current in episode list)",
+		"  r             Refresh due feeds (podcast list) or force current podcast (episode list)",
+		"  N             Jump to next new episode found by the latest refresh",
+		"  H             Show feed health and recent errors (r to retry)",
+		"",
+		"  Note: Feeds also refresh in the background on startup and periodically",
+		"  Note: Each feed is due based on how often it publishes; failing feeds back off",
+		"  Note: Moved feeds are followed; gone (410) feeds are retired (⊘)",
 		"",
 		"Other:",
 		"  :             Enter command mode",
@@ -320,7 +331,18 @@ func (h *HelpDialog) getHelpContent() []string {
 		"  Q             Quit application (uppercase Q required)",
 		"",
 		"Command Mode:",
-		"  :add <url>    Add new podcast by RSS feed URL",
+		"  :add <url>    Add new podcast by feed or website URL (user:pass@host for private feeds)",
+		"  :add <folder> Add a local folder of audio files as a podcast",
+		"  :auth <user> <pass>   Set login for selected podcast (:auth clear)",
+		"  :header <Name>: <val> Set header for selected podcast (:header <Name> removes)",
+		"  :refresh      Refresh feeds that are due",
+		"  :refresh all  Refresh every feed, due or not",
+		"  :history      Fetch all pages of selected podcast's feed",
+		"  :media [audio|video|<type>|any] [high|low|default]  Preferred media of selected podcast",
+		"  :sleep <min>|episode|chapter|off  Pause after a while, or stop at end of episode/chapter",
+		"  :filter [podcast] silence|normalize|voice [on|off|default]  Toggle audio filters (:af)",
+		"  :skip intro|outro <sec>|off  Skip the podcast's intro, or end its episodes at the outro",
+		"  :directory <terms>    Search podcast directory (:dir); Enter subscribes, Esc returns",
 		"  :q            Go to queue view (from podcast/episode view)",
 		"  :Q or :quit   Quit the application",
 		"",
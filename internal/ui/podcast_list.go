@@ -21,18 +21,26 @@ func (r *PodcastTableRow) GetCell(columnIndex int) string {
 	switch columnIndex {
 	case 0: // Status column (selection indicator handled by table)
 		return ""
-	case 1: // Caught up indicator
+	case 1: // Feed health indicator
+		if r.podcast.Retired {
+			return "⊘"
+		}
+		if r.podcast.HasRefreshErrors() {
+			return "⚠"
+		}
+		return ""
+	case 2: // Caught up indicator
 		if r.isCaughtUp() {
 			return "✔"
 		}
 		return ""
-	case 2: // Title
+	case 3: // Title
 		return r.podcast.Title
-	case 3: // URL
+	case 4: // URL
 		return r.podcast.URL
-	case 4: // Latest episode date
+	case 5: // Latest episode date
 		return r.getLatestEpisodeDate()
-	case 5: // Episode count
+	case 6: // Episode count
 		return fmt.Sprintf("%d eps", len(r.podcast.Episodes))
 	default:
 		return ""
@@ -40,7 +48,20 @@ func (r *PodcastTableRow) GetCell(columnIndex int) string {
 }
 
 func (r *PodcastTableRow) GetCellStyle(columnIndex int, selected bool) *tcell.Style {
-	if columnIndex == 1 && r.isCaughtUp() {
+	if columnIndex == 1 && (r.podcast.Retired || r.podcast.HasRefreshErrors()) {
+		// Yellow while failing, red once the feed looks broken, dimmed once retired
+		style := tcell.StyleDefault.Foreground(ColorYellow)
+		if r.podcast.Retired {
+			style = tcell.StyleDefault.Foreground(ColorDimmed)
+		} else if r.podcast.IsBroken() {
+			style = tcell.StyleDefault.Foreground(ColorError)
+		}
+		if selected {
+			style = style.Background(ColorSelection)
+		}
+		return &style
+	}
+	if columnIndex == 2 && r.isCaughtUp() {
 		// Style the checkmark in green
 		style := tcell.StyleDefault.Foreground(tcell.ColorGreen)
 		if selected {
@@ -57,7 +78,7 @@ func (r *PodcastTableRow) GetHighlightPositions(columnIndex int) []int {
 	}
 	
 	switch columnIndex {
-	case 2: // Title
+	case 3: // Title
 		if r.matchResult.MatchField == "title" {
 			return r.matchResult.Positions
 		}
@@ -146,6 +167,7 @@ func NewPodcastListView() *PodcastListView {
 	// Configure table columns
 	v.table.SetColumns([]TableColumn{
 		{Title: "", Width: 2, Align: AlignLeft},                    // Status
+		{Title: "", Width: 2, Align: AlignLeft},                    // Feed health
 		{Title: "Caught Up", Width: 10, Align: AlignCenter},        // Caught up indicator
 		{Title: "Title", MinWidth: 20, FlexWeight: 0.6, Align: AlignLeft},   // Title
 		{Title: "Feed URL", MinWidth: 20, FlexWeight: 0.4, Align: AlignLeft}, // URL
@@ -15,7 +15,7 @@ type QueueView struct {
 	subscriptions   *models.Subscriptions
 	episodes        []*models.Episode
 	downloadManager *download.Manager
-	player          *player.Player
+	player          player.Backend
 	currentEpisode  *models.Episode
 }
 
@@ -24,7 +24,7 @@ type QueueTableRow struct {
 	podcast         *models.Podcast
 	queuePosition   int
 	currentEpisode  *models.Episode
-	player          *player.Player
+	player          player.Backend
 	downloadManager *download.Manager
 }
 
@@ -55,7 +55,7 @@ func (v *QueueView) SetDownloadManager(dm *download.Manager) {
 	v.downloadManager = dm
 }
 
-func (v *QueueView) SetPlayer(p *player.Player) {
+func (v *QueueView) SetPlayer(p player.Backend) {
 	v.player = p
 }
 
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+// startupRefreshDelay gives the UI a moment to settle before the startup refresh
+const startupRefreshDelay = 5 * time.Second
+
+// maxTrackedNewEpisodes limits how many new episodes are remembered for 'N'
+const maxTrackedNewEpisodes = 100
+
+// runRefreshScheduler refreshes subscriptions on startup and then periodically
+// according to the settings, until the app quits
+func (a *App) runRefreshScheduler() {
+	if a.settings.RefreshOnStartup {
+		select {
+		case <-a.quit:
+			return
+		case <-time.After(startupRefreshDelay):
+			a.scheduledRefresh()
+		}
+	}
+
+	interval := a.settings.RefreshInterval()
+	if interval <= 0 {
+		log.Printf("Periodic feed refresh disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.quit:
+			return
+		case <-ticker.C:
+			a.scheduledRefresh()
+		}
+	}
+}
+
+// scheduledRefresh refreshes every podcast that is due and hasn't been refreshed recently
+func (a *App) scheduledRefresh() {
+	if a.subscriptions == nil {
+		return
+	}
+
+	minAge := a.settings.RefreshMinAge()
+	now := time.Now()
+
+	var stale []*models.Podcast
+	for _, podcast := range a.duePodcasts(now) {
+		if minAge > 0 && now.Sub(podcast.LastUpdated) < minAge {
+			continue
+		}
+		stale = append(stale, podcast)
+	}
+
+	log.Printf("Scheduled refresh: %d of %d podcasts need refreshing",
+		len(stale), len(a.subscriptions.Podcasts))
+	a.refreshPodcasts(stale, true)
+}
+
+// duePodcasts returns the podcasts whose adaptive refresh interval has elapsed
+func (a *App) duePodcasts(now time.Time) []*models.Podcast {
+	var due []*models.Podcast
+	for _, podcast := range a.subscriptions.Podcasts {
+		if podcast.IsRefreshDue(now) {
+			due = append(due, podcast)
+		}
+	}
+	return due
+}
+
+// startDueRefresh starts a manual refresh of the podcasts that are due, or explains
+// when the next one will be due if none are
+func (a *App) startDueRefresh() {
+	if len(a.subscriptions.Podcasts) == 0 {
+		a.statusMessage = "No podcasts to refresh"
+		return
+	}
+
+	now := time.Now()
+	due := a.duePodcasts(now)
+	if len(due) == 0 {
+		var next *models.Podcast
+		for _, podcast := range a.subscriptions.Podcasts {
+			if next == nil || podcast.NextRefresh.Before(next.NextRefresh) {
+				next = podcast
+			}
+		}
+		a.statusMessage = fmt.Sprintf("No podcasts due; next is %s in %v (:refresh all to force)",
+			next.Title, next.NextRefresh.Sub(now).Round(time.Minute))
+		return
+	}
+
+	a.statusMessage = fmt.Sprintf("Starting refresh of %d of %d podcasts (due)...",
+		len(due), len(a.subscriptions.Podcasts))
+	go a.refreshPodcasts(due, false)
+}
+
+// waitForHost blocks until a request to the feed's host is allowed, reserving
+// the next slot so concurrent refreshes of feeds on the same host are staggered
+func (a *App) waitForHost(feedURL string) {
+	delay := a.settings.RefreshHostDelay()
+	if delay <= 0 {
+		return
+	}
+
+	u, err := url.Parse(feedURL)
+	if err != nil || u.Host == "" {
+		return
+	}
+
+	a.refreshMutex.Lock()
+	now := time.Now()
+	slot := a.hostNextFetch[u.Host]
+	if slot.Before(now) {
+		slot = now
+	}
+	a.hostNextFetch[u.Host] = slot.Add(delay)
+	a.refreshMutex.Unlock()
+
+	if wait := time.Until(slot); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// recordNewEpisodes remembers episodes found by a refresh so the user can jump to them
+func (a *App) recordNewEpisodes(episodes []*models.Episode) {
+	if len(episodes) == 0 {
+		return
+	}
+
+	a.refreshMutex.Lock()
+	defer a.refreshMutex.Unlock()
+
+	// Newest first so 'N' starts with the most recent release
+	a.newEpisodes = append(append([]*models.Episode{}, episodes...), a.newEpisodes...)
+	sort.SliceStable(a.newEpisodes, func(i, j int) bool {
+		return a.newEpisodes[i].PublishDate.After(a.newEpisodes[j].PublishDate)
+	})
+	if len(a.newEpisodes) > maxTrackedNewEpisodes {
+		a.newEpisodes = a.newEpisodes[:maxTrackedNewEpisodes]
+	}
+	a.newEpisodeCursor = 0
+}
+
+// jumpToNextNewEpisode shows the next new episode in the episode list, cycling through them
+func (a *App) jumpToNextNewEpisode() {
+	a.refreshMutex.Lock()
+	if len(a.newEpisodes) == 0 {
+		a.refreshMutex.Unlock()
+		a.statusMessage = "No new episodes"
+		return
+	}
+	if a.newEpisodeCursor >= len(a.newEpisodes) {
+		a.newEpisodeCursor = 0
+	}
+	index := a.newEpisodeCursor
+	episode := a.newEpisodes[index]
+	total := len(a.newEpisodes)
+	a.newEpisodeCursor++
+	a.refreshMutex.Unlock()
+
+	podcast := a.subscriptions.GetPodcastForEpisode(episode.ID)
+	if podcast == nil {
+		a.statusMessage = "Episode no longer available"
+		return
+	}
+
+	a.episodes.SetPodcast(podcast)
+	a.currentView = a.episodes
+	a.selectEpisodeInList(episode.ID)
+	a.statusMessage = fmt.Sprintf("New episode %d/%d: %s", index+1, total, podcast.Title)
+}
+
+// formatNewEpisodeSummary describes how many new episodes a refresh found
+func formatNewEpisodeSummary(episodeCount, podcastCount int) string {
+	if episodeCount == 0 {
+		return ""
+	}
+
+	episodes := "episodes"
+	if episodeCount == 1 {
+		episodes = "episode"
+	}
+	podcasts := "podcasts"
+	if podcastCount == 1 {
+		podcasts = "podcast"
+	}
+	return fmt.Sprintf("%d new %s across %d %s (N to view)", episodeCount, episodes, podcastCount, podcasts)
+}
@@ -4,6 +4,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/directory"
+	"github.com/csams/podcast-tui/internal/feed"
+	"github.com/csams/podcast-tui/internal/player"
+	"github.com/csams/podcast-tui/internal/privacy"
 )
 
 // Settings holds the application UI settings
@@ -16,6 +22,68 @@ type Settings struct {
 	// Use {file} as a placeholder for the file path
 	// Default depends on the terminal
 	TerminalArgs []string `json:"terminalArgs,omitempty"`
+
+	// RefreshIntervalMinutes controls how often subscriptions are refreshed in the background
+	// Set to 0 to disable periodic refreshes
+	// Default: 60
+	RefreshIntervalMinutes int `json:"refreshIntervalMinutes"`
+
+	// RefreshOnStartup refreshes subscriptions shortly after the app starts
+	// Default: true
+	RefreshOnStartup bool `json:"refreshOnStartup"`
+
+	// RefreshMinAgeMinutes skips podcasts refreshed more recently than this
+	// during background refreshes
+	// Default: 30
+	RefreshMinAgeMinutes int `json:"refreshMinAgeMinutes"`
+
+	// RefreshHostDelaySeconds is the minimum delay between requests to the same host
+	// Default: 2
+	RefreshHostDelaySeconds int `json:"refreshHostDelaySeconds"`
+
+	// MaxFeedSizeMB is the largest feed, after decompression, that will be parsed
+	// Default: 50
+	MaxFeedSizeMB int `json:"maxFeedSizeMB"`
+
+	// HistoryMaxPages is the most pages of a paginated feed fetched when subscribing
+	// or with ":history"
+	// Default: 50
+	HistoryMaxPages int `json:"historyMaxPages"`
+
+	// HistoryMaxEpisodes stops following a paginated feed once this many episodes are known
+	// Default: 5000
+	HistoryMaxEpisodes int `json:"historyMaxEpisodes"`
+
+	// DirectoryProvider is the podcast directory searched by ":directory",
+	// "itunes" or "podcastindex"
+	// Default: "itunes"
+	DirectoryProvider string `json:"directoryProvider"`
+
+	// DirectoryBaseURL overrides the directory's API location
+	// Default: the provider's public API
+	DirectoryBaseURL string `json:"directoryBaseURL,omitempty"`
+
+	// PrivacyMode removes known tracking prefixes from episode URLs before they
+	// are streamed or downloaded
+	// Default: false
+	PrivacyMode bool `json:"privacyMode"`
+
+	// UserAgent is sent with feed and download requests
+	// Default: privacy.DefaultUserAgent
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// AudioFilters lists the audio filters applied to every podcast: "silence",
+	// "normalize" and "voice". Podcasts can override each with ":filter podcast".
+	// Default: none
+	AudioFilters []string `json:"audioFilters,omitempty"`
+
+	// SmartRewind goes back a little when resuming after a pause, or resuming an
+	// episode's saved position: SmartRewindMinSeconds after a minute away,
+	// growing to SmartRewindMaxSeconds after an hour
+	// Default: false, 3 and 30
+	SmartRewind           bool `json:"smartRewind"`
+	SmartRewindMinSeconds int  `json:"smartRewindMinSeconds"`
+	SmartRewindMaxSeconds int  `json:"smartRewindMaxSeconds"`
 }
 
 // DefaultSettings returns the default settings
@@ -23,6 +91,91 @@ func DefaultSettings() *Settings {
 	return &Settings{
 		Terminal:     "kitty",
 		TerminalArgs: []string{"nvim", "{file}"},
+
+		RefreshIntervalMinutes:  60,
+		RefreshOnStartup:        true,
+		RefreshMinAgeMinutes:    30,
+		RefreshHostDelaySeconds: 2,
+
+		MaxFeedSizeMB: 50,
+
+		HistoryMaxPages:    feed.DefaultMaxHistoryPages,
+		HistoryMaxEpisodes: feed.DefaultMaxHistoryEpisodes,
+
+		DirectoryProvider: directory.ProviderITunes,
+
+		SmartRewindMinSeconds: 3,
+		SmartRewindMaxSeconds: 30,
+	}
+}
+
+// RefreshInterval returns the background refresh interval (0 if disabled)
+func (s *Settings) RefreshInterval() time.Duration {
+	if s.RefreshIntervalMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(s.RefreshIntervalMinutes) * time.Minute
+}
+
+// RefreshMinAge returns how recently a podcast may have been refreshed before
+// background refreshes skip it
+func (s *Settings) RefreshMinAge() time.Duration {
+	if s.RefreshMinAgeMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(s.RefreshMinAgeMinutes) * time.Minute
+}
+
+// RefreshHostDelay returns the minimum delay between requests to the same host
+func (s *Settings) RefreshHostDelay() time.Duration {
+	if s.RefreshHostDelaySeconds <= 0 {
+		return 0
+	}
+	return time.Duration(s.RefreshHostDelaySeconds) * time.Second
+}
+
+// MaxFeedBytes returns the feed size limit in bytes (0 means the parser default)
+func (s *Settings) MaxFeedBytes() int64 {
+	if s.MaxFeedSizeMB <= 0 {
+		return 0
+	}
+	return int64(s.MaxFeedSizeMB) << 20
+}
+
+// HistoryLimits returns the limits for fetching the history of paginated feeds
+func (s *Settings) HistoryLimits() feed.HistoryLimits {
+	return feed.HistoryLimits{
+		MaxPages:    s.HistoryMaxPages,
+		MaxEpisodes: s.HistoryMaxEpisodes,
+	}
+}
+
+// DirectoryConfig returns the configuration of the podcast directory, without
+// the API key, which is kept with the credentials
+func (s *Settings) DirectoryConfig() directory.Config {
+	return directory.Config{
+		Provider:  s.DirectoryProvider,
+		BaseURL:   s.DirectoryBaseURL,
+		UserAgent: s.HTTPUserAgent(),
+	}
+}
+
+// HTTPUserAgent returns the User-Agent for feed, download and directory requests
+func (s *Settings) HTTPUserAgent() string {
+	if s.UserAgent == "" {
+		return privacy.DefaultUserAgent
+	}
+	return s.UserAgent
+}
+
+// Rewind returns how far to go back on resuming, nothing if smart rewind is off
+func (s *Settings) Rewind() player.SmartRewind {
+	if !s.SmartRewind {
+		return player.SmartRewind{}
+	}
+	return player.SmartRewind{
+		Min: time.Duration(s.SmartRewindMinSeconds) * time.Second,
+		Max: time.Duration(s.SmartRewindMaxSeconds) * time.Second,
 	}
 }
 
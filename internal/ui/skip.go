@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/models"
+)
+
+const skipUsage = "Usage: skip intro|outro <seconds>|<duration>|off"
+
+// startPosition returns where to start episode: its saved position, rewound
+// as in rewoundPosition, or past its podcast's intro if it was never played
+func (a *App) startPosition(episode *models.Episode, lastHeard time.Time) time.Duration {
+	if episode.Position > 0 && episode.Position < time.Hour*24 {
+		return a.rewoundPosition(episode.Position, lastHeard)
+	}
+	if episode.Played || episode.Position != 0 {
+		return 0
+	}
+	podcast := a.subscriptions.GetPodcastForEpisode(episode.ID)
+	if podcast == nil || podcast.SkipIntro <= 0 {
+		return 0
+	}
+	if episode.Duration > 0 && podcast.SkipIntro >= episode.Duration {
+		// Nothing would be left to hear
+		return 0
+	}
+	return podcast.SkipIntro
+}
+
+// outroFor returns how much of the end of podcast's episodes is skipped
+func outroFor(podcast *models.Podcast) time.Duration {
+	if podcast == nil {
+		return 0
+	}
+	return podcast.SkipOutro
+}
+
+// applyOutro has the player end the current episode at its podcast's outro,
+// which then counts as the end of the episode
+func (a *App) applyOutro() {
+	if err := a.player.SetOutro(outroFor(a.currentPodcast)); err != nil {
+		log.Printf("Failed to set the outro: %v", err)
+	}
+}
+
+// episodeEnd returns where the current episode of the given duration stops
+// playing: at the outro, if it has one and is long enough
+func (a *App) episodeEnd(duration time.Duration) time.Duration {
+	if outro := outroFor(a.currentPodcast); outro > 0 && duration > outro {
+		return duration - outro
+	}
+	return duration
+}
+
+// setSkip handles ":skip intro|outro <seconds>|<duration>|off", which sets how
+// much of the start or end of the selected podcast's episodes is skipped;
+// without arguments it shows the podcast's settings
+func (a *App) setSkip(args []string) {
+	podcast := a.commandTarget()
+	if podcast == nil {
+		a.statusMessage = "Select a podcast first"
+		return
+	}
+	if len(args) == 0 {
+		a.statusMessage = a.describeSkip(podcast)
+		return
+	}
+	if len(args) != 2 {
+		a.statusMessage = skipUsage
+		return
+	}
+
+	skip, err := parseSkipDuration(strings.ToLower(args[1]))
+	if err != nil {
+		a.statusMessage = skipUsage
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "intro":
+		podcast.SkipIntro = skip
+	case "outro":
+		podcast.SkipOutro = skip
+	default:
+		a.statusMessage = skipUsage
+		return
+	}
+	if err := a.subscriptions.Save(); err != nil {
+		log.Printf("Failed to save skip settings: %v", err)
+	}
+
+	a.statusMessage = a.describeSkip(podcast)
+	if a.currentEpisode != nil {
+		if podcast == a.currentPodcast {
+			a.applyOutro()
+		}
+		a.preloadNext()
+	}
+}
+
+// parseSkipDuration reads a number of seconds, a duration such as "1m30s", or
+// "off" for none
+func parseSkipDuration(arg string) (time.Duration, error) {
+	if arg == "off" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(arg); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("skip must not be negative")
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(arg)
+	if err == nil && d < 0 {
+		err = fmt.Errorf("skip must not be negative")
+	}
+	return d, err
+}
+
+// describeSkip tells how much of podcast's episodes is skipped
+func (a *App) describeSkip(podcast *models.Podcast) string {
+	describe := func(d time.Duration) string {
+		if d <= 0 {
+			return "off"
+		}
+		return a.formatTime(d)
+	}
+	return fmt.Sprintf("%s: skip intro %s, outro %s", podcast.Title, describe(podcast.SkipIntro), describe(podcast.SkipOutro))
+}
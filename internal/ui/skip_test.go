@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApp_SkipIntroAndOutro(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 2, 10*time.Minute)
+	podcast := app.subscriptions.Podcasts[0]
+	app.episodes.SetPodcast(podcast)
+	app.currentView = app.episodes
+
+	app.setSkip([]string{"intro", "60"})
+	app.setSkip([]string{"outro", "2m"})
+	if podcast.SkipIntro != time.Minute || podcast.SkipOutro != 2*time.Minute {
+		t.Fatalf("Expected a 1:00 intro and 2:00 outro, got %v and %v", podcast.SkipIntro, podcast.SkipOutro)
+	}
+	if !strings.Contains(app.statusMessage, "skip intro") {
+		t.Errorf("Expected the settings shown, got %q", app.statusMessage)
+	}
+
+	// Unplayed episodes start after the intro
+	app.addToQueue(episodes[0])
+	app.addToQueue(episodes[1])
+	settle(app, fake)
+	if position, _ := fake.GetPosition(); position != time.Minute {
+		t.Errorf("Expected the intro skipped, at %v", position)
+	}
+	if url, start := fake.Preloaded(); url != episodes[1].URL || start != time.Minute {
+		t.Errorf("Expected the next episode preloaded after its intro, got %q at %v", url, start)
+	}
+
+	// Reaching the outro finishes the episode and moves on
+	fake.Advance(7*time.Minute + 30*time.Second)
+	if episodes[0].Played {
+		t.Fatalf("Expected the episode still playing before the outro")
+	}
+	fake.Advance(30 * time.Second)
+	settle(app, fake)
+	if app.currentEpisode == nil || app.currentEpisode.ID != episodes[1].ID {
+		t.Fatalf("Expected the second episode to play at the outro")
+	}
+	if !episodes[0].Played || app.subscriptions.GetQueuePosition(episodes[0].ID) != 0 {
+		t.Errorf("Expected the first episode played and out of the queue")
+	}
+
+	fake.Finish()
+	settle(app, fake)
+	if app.currentEpisode != nil || !episodes[1].Played {
+		t.Errorf("Expected the second episode played at its outro")
+	}
+
+	// Played episodes start from the beginning
+	episodes[0].Position = 0
+	if start := app.startPosition(episodes[0], time.Time{}); start != 0 {
+		t.Errorf("Expected a played episode to start at 0, got %v", start)
+	}
+
+	app.setSkip([]string{"intro", "soon"})
+	if app.statusMessage != skipUsage {
+		t.Errorf("Expected a usage message, got %q", app.statusMessage)
+	}
+	app.setSkip([]string{"outro", "off"})
+	if podcast.SkipOutro != 0 {
+		t.Errorf("Expected the outro off, got %v", podcast.SkipOutro)
+	}
+}
@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/player"
+)
+
+// sleepFade is how long before the sleep timer runs out the volume starts fading
+const sleepFade = 30 * time.Second
+
+// sleepStep is how much listening time z adds to the sleep timer
+const sleepStep = 15 * time.Minute
+
+type sleepMode int
+
+const (
+	sleepOff sleepMode = iota
+	sleepAfter
+	sleepEndOfEpisode
+	sleepEndOfChapter
+)
+
+// sleepTimer pauses playback after some listening time, or stops it at the end
+// of the episode or chapter. It is advanced by the player's progress, so time
+// spent paused doesn't count.
+type sleepTimer struct {
+	mu         sync.Mutex
+	mode       sleepMode
+	left       time.Duration // listening time left, for sleepAfter
+	chapterEnd time.Duration // where the chapter being played ends, for sleepEndOfChapter
+	episodeID  string        // the episode whose chapter is being played
+	lastTick   time.Time
+	fading     bool
+	volume     int // volume before fading started, restored afterwards
+}
+
+// remaining returns how long until the timer runs out at position in a file of
+// the given duration, if that is known
+func (t *sleepTimer) remaining(position, duration time.Duration) (time.Duration, bool) {
+	switch t.mode {
+	case sleepAfter:
+		return t.left, true
+	case sleepEndOfEpisode:
+		return duration - position, duration > 0
+	case sleepEndOfChapter:
+		return t.chapterEnd - position, true
+	}
+	return 0, false
+}
+
+// advance counts down the time played since the last tick and returns how
+// long is left
+func (t *sleepTimer) advance(now time.Time, playing bool, position, duration time.Duration) (time.Duration, bool) {
+	if !playing {
+		t.lastTick = time.Time{}
+	} else {
+		if t.mode == sleepAfter && !t.lastTick.IsZero() {
+			t.left -= now.Sub(t.lastTick)
+		}
+		t.lastTick = now
+	}
+	return t.remaining(position, duration)
+}
+
+// reset turns the timer off, returning the volume to restore if it was fading
+func (t *sleepTimer) reset() (int, bool) {
+	volume, fading := t.volume, t.fading
+	t.mode = sleepOff
+	t.left = 0
+	t.chapterEnd = 0
+	t.episodeID = ""
+	t.lastTick = time.Time{}
+	t.fading = false
+	t.volume = 0
+	return volume, fading
+}
+
+// fadeVolume returns the volume to play at when left is all that remains,
+// falling linearly from volume to nothing over sleepFade
+func fadeVolume(volume int, left time.Duration) int {
+	if left >= sleepFade {
+		return volume
+	}
+	if left <= 0 {
+		return 0
+	}
+	return int(float64(volume) * float64(left) / float64(sleepFade))
+}
+
+// chapterEnd returns where the chapter playing at position ends: the start of
+// the next chapter, or the end of the file for the last one
+func chapterEnd(chapters []player.Chapter, position, duration time.Duration) (time.Duration, bool) {
+	if len(chapters) == 0 {
+		return 0, false
+	}
+	for _, chapter := range chapters {
+		if chapter.Start > position {
+			return chapter.Start, true
+		}
+	}
+	return duration, duration > 0
+}
+
+// setSleepTimer handles ":sleep <minutes>|<duration>|episode|chapter|off";
+// without arguments it shows the timer
+func (a *App) setSleepTimer(args []string) {
+	if len(args) == 0 {
+		a.statusMessage = "Sleep timer: " + a.describeSleepTimer()
+		return
+	}
+
+	arg := strings.ToLower(args[0])
+	switch arg {
+	case "off", "cancel":
+		a.cancelSleepTimer()
+		return
+	case "episode", "chapter":
+		if a.player.GetState() == player.StateStopped || a.currentEpisode == nil {
+			a.statusMessage = "Nothing is playing"
+			return
+		}
+	}
+
+	a.sleep.mu.Lock()
+	volume, fading := a.sleep.reset()
+	switch arg {
+	case "episode":
+		a.sleep.mode = sleepEndOfEpisode
+	case "chapter":
+		position, _ := a.player.GetPosition()
+		duration, _ := a.player.GetDuration()
+		end, ok := chapterEnd(a.player.Chapters(), position, duration)
+		if !ok {
+			a.sleep.mu.Unlock()
+			a.restoreSleepVolume(volume, fading)
+			a.statusMessage = "This episode has no chapters"
+			return
+		}
+		a.sleep.mode = sleepEndOfChapter
+		a.sleep.chapterEnd = end
+		a.sleep.episodeID = a.currentEpisode.ID
+		if end >= duration {
+			// The last chapter ends with the episode
+			a.sleep.mode = sleepEndOfEpisode
+		}
+	default:
+		left, err := parseSleepDuration(arg)
+		if err != nil {
+			a.sleep.mu.Unlock()
+			a.restoreSleepVolume(volume, fading)
+			a.statusMessage = "Usage: sleep <minutes>|<duration>|episode|chapter|off"
+			return
+		}
+		a.sleep.mode = sleepAfter
+		a.sleep.left = left
+	}
+	a.sleep.mu.Unlock()
+
+	a.restoreSleepVolume(volume, fading)
+	a.preloadNext()
+	a.statusMessage = "Sleep timer: " + a.describeSleepTimer()
+}
+
+// parseSleepDuration reads a number of minutes, or a duration such as "1h30m"
+func parseSleepDuration(arg string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(arg); err == nil {
+		if minutes <= 0 {
+			return 0, fmt.Errorf("sleep timer must be positive")
+		}
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(arg)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("sleep timer must be positive")
+	}
+	return d, err
+}
+
+// extendSleepTimer starts a sleep timer, or adds another sleepStep of
+// listening to the running one
+func (a *App) extendSleepTimer() {
+	a.sleep.mu.Lock()
+	if a.sleep.mode != sleepAfter {
+		a.sleep.reset()
+		a.sleep.mode = sleepAfter
+	}
+	a.sleep.left += sleepStep
+	volume, fading := a.sleep.volume, a.sleep.fading
+	a.sleep.fading = false
+	a.sleep.mu.Unlock()
+
+	a.restoreSleepVolume(volume, fading)
+	a.preloadNext()
+	a.statusMessage = "Sleep timer: " + a.describeSleepTimer()
+}
+
+// cancelSleepTimer turns the sleep timer off, restoring the volume if it was fading
+func (a *App) cancelSleepTimer() {
+	a.sleep.mu.Lock()
+	wasOn := a.sleep.mode != sleepOff
+	volume, fading := a.sleep.reset()
+	a.sleep.mu.Unlock()
+
+	a.restoreSleepVolume(volume, fading)
+	a.preloadNext()
+	if wasOn {
+		a.statusMessage = "Sleep timer cancelled"
+	} else {
+		a.statusMessage = "Sleep timer: off"
+	}
+}
+
+func (a *App) restoreSleepVolume(volume int, fading bool) {
+	if !fading {
+		return
+	}
+	if err := a.player.SetVolume(volume); err != nil {
+		log.Printf("Failed to restore volume after sleep timer: %v", err)
+	}
+}
+
+// tickSleepTimer advances the sleep timer with the player's progress, fading
+// the volume over its last 30 seconds and pausing when it runs out. A timer
+// for the end of the episode runs out in handleEpisodeCompletion instead.
+func (a *App) tickSleepTimer() {
+	playing := a.player.IsPlaying()
+	position, _ := a.player.GetPosition()
+	duration, _ := a.player.GetDuration()
+	duration = a.episodeEnd(duration)
+
+	a.sleep.mu.Lock()
+	if a.sleep.mode == sleepOff {
+		a.sleep.mu.Unlock()
+		return
+	}
+	left, ok := a.sleep.advance(time.Now(), playing, position, duration)
+	if a.sleep.mode == sleepEndOfChapter && (a.currentEpisode == nil || a.currentEpisode.ID != a.sleep.episodeID) {
+		// The episode with the chapter is over
+		left, ok = 0, true
+	}
+	if !ok || !playing {
+		a.sleep.mu.Unlock()
+		return
+	}
+
+	if left <= 0 && a.sleep.mode != sleepEndOfEpisode {
+		volume, fading := a.sleep.reset()
+		a.sleep.mu.Unlock()
+
+		a.saveEpisodePosition()
+		if err := a.player.Pause(); err != nil {
+			log.Printf("Sleep timer failed to pause: %v", err)
+		}
+		a.restoreSleepVolume(volume, fading)
+		if err := a.subscriptions.Save(); err != nil {
+			log.Printf("Failed to save position for sleep timer: %v", err)
+		}
+		a.statusMessage = "Sleep timer: paused"
+		a.draw()
+		return
+	}
+
+	if left >= sleepFade {
+		a.sleep.mu.Unlock()
+		return
+	}
+	if !a.sleep.fading {
+		a.sleep.fading = true
+		a.sleep.volume, _ = a.player.GetVolume()
+	}
+	target := fadeVolume(a.sleep.volume, left)
+	a.sleep.mu.Unlock()
+
+	if current, _ := a.player.GetVolume(); current != target {
+		if err := a.player.SetVolume(target); err != nil {
+			log.Printf("Sleep timer failed to fade volume: %v", err)
+		}
+	}
+}
+
+// sleepAtEndOfEpisode reports whether the sleep timer runs out with the episode
+// that just finished, turning it off
+func (a *App) sleepAtEndOfEpisode() bool {
+	a.sleep.mu.Lock()
+	if a.sleep.mode != sleepEndOfEpisode {
+		a.sleep.mu.Unlock()
+		return false
+	}
+	volume, fading := a.sleep.reset()
+	a.sleep.mu.Unlock()
+
+	a.restoreSleepVolume(volume, fading)
+	return true
+}
+
+// sleepsAtEndOfEpisode reports whether the sleep timer stops playback when the
+// current episode ends
+func (a *App) sleepsAtEndOfEpisode() bool {
+	a.sleep.mu.Lock()
+	defer a.sleep.mu.Unlock()
+	return a.sleep.mode == sleepEndOfEpisode
+}
+
+// describeSleepTimer tells how long the sleep timer has left
+func (a *App) describeSleepTimer() string {
+	position, _ := a.player.GetPosition()
+	duration, _ := a.player.GetDuration()
+	duration = a.episodeEnd(duration)
+
+	a.sleep.mu.Lock()
+	defer a.sleep.mu.Unlock()
+	left, known := a.sleep.remaining(position, duration)
+	switch a.sleep.mode {
+	case sleepAfter:
+		return fmt.Sprintf("pausing after %s of listening", a.formatTime(left))
+	case sleepEndOfEpisode:
+		if known {
+			return fmt.Sprintf("stopping at the end of the episode (%s)", a.formatTime(left))
+		}
+		return "stopping at the end of the episode"
+	case sleepEndOfChapter:
+		return fmt.Sprintf("pausing at the end of the chapter (%s)", a.formatTime(left))
+	}
+	return "off"
+}
+
+// sleepCountdown is the status bar's sleep timer countdown, or "" when it's off
+func (a *App) sleepCountdown(position, duration time.Duration) string {
+	a.sleep.mu.Lock()
+	defer a.sleep.mu.Unlock()
+	if a.sleep.mode == sleepOff {
+		return ""
+	}
+	left, known := a.sleep.remaining(position, a.episodeEnd(duration))
+	if !known {
+		return "[Sleep: end]"
+	}
+	return fmt.Sprintf("[Sleep %s]", a.formatTime(max(left, 0)))
+}
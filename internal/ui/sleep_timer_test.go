@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csams/podcast-tui/internal/player"
+)
+
+func TestFadeVolume(t *testing.T) {
+	tests := []struct {
+		left time.Duration
+		want int
+	}{
+		{time.Minute, 80},
+		{30 * time.Second, 80},
+		{15 * time.Second, 40},
+		{3 * time.Second, 8},
+		{0, 0},
+		{-time.Second, 0},
+	}
+	for _, tt := range tests {
+		if got := fadeVolume(80, tt.left); got != tt.want {
+			t.Errorf("fadeVolume(80, %v) = %d, want %d", tt.left, got, tt.want)
+		}
+	}
+}
+
+func TestChapterEnd(t *testing.T) {
+	chapters := []player.Chapter{{Title: "Intro"}, {Title: "News", Start: 2 * time.Minute}, {Title: "Outro", Start: 9 * time.Minute}}
+	tests := []struct {
+		position time.Duration
+		want     time.Duration
+	}{
+		{0, 2 * time.Minute},
+		{3 * time.Minute, 9 * time.Minute},
+		{9*time.Minute + time.Second, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got, ok := chapterEnd(chapters, tt.position, 10*time.Minute); !ok || got != tt.want {
+			t.Errorf("chapterEnd at %v = %v, want %v", tt.position, got, tt.want)
+		}
+	}
+	if _, ok := chapterEnd(nil, 0, 10*time.Minute); ok {
+		t.Error("Expected no chapter end without chapters")
+	}
+}
+
+func TestApp_SleepTimerFadesAndPauses(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 1, time.Hour)
+	app.playEpisode(episodes[0])
+
+	app.setSleepTimer([]string{"10"})
+	if !strings.Contains(app.formatPlayerStatus(200), "[Sleep 10:00]") {
+		t.Errorf("Expected the countdown in the status bar, got %q", app.formatPlayerStatus(200))
+	}
+
+	// Paused time doesn't count
+	app.tickSleepTimer()
+	fake.Pause()
+	app.tickSleepTimer()
+	time.Sleep(20 * time.Millisecond)
+	app.tickSleepTimer()
+	fake.Resume()
+	app.tickSleepTimer()
+	if app.sleep.left < 10*time.Minute-10*time.Millisecond {
+		t.Errorf("Expected paused time not to count, %v left", app.sleep.left)
+	}
+
+	// The volume fades over the last 30 seconds
+	app.sleep.left = 15 * time.Second
+	app.tickSleepTimer()
+	if volume, _ := fake.GetVolume(); volume < 45 || volume > 50 {
+		t.Errorf("Expected the volume halfway faded, got %d", volume)
+	}
+
+	fake.Advance(10 * time.Minute)
+	app.sleep.left = 0
+	app.tickSleepTimer()
+	if !fake.IsPaused() || !strings.Contains(app.statusMessage, "paused") {
+		t.Errorf("Expected playback paused, got state %v and status %q", fake.GetState(), app.statusMessage)
+	}
+	if volume, _ := fake.GetVolume(); volume != 100 {
+		t.Errorf("Expected the volume restored, got %d", volume)
+	}
+	if episodes[0].Position != 10*time.Minute {
+		t.Errorf("Expected the position saved, got %v", episodes[0].Position)
+	}
+	if app.sleep.mode != sleepOff || strings.Contains(app.formatPlayerStatus(200), "Sleep") {
+		t.Error("Expected the timer to be off")
+	}
+}
+
+func TestApp_SleepTimerEndOfEpisode(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 2, 10*time.Minute)
+	app.addToQueue(episodes[0])
+	app.addToQueue(episodes[1])
+
+	app.setSleepTimer([]string{"episode"})
+	fake.Finish()
+	settle(app, fake)
+
+	if app.currentEpisode != nil || !episodes[0].Played || !strings.Contains(app.statusMessage, "Sleep timer") {
+		t.Errorf("Expected the episode finished by the sleep timer, got played=%v status=%q", episodes[0].Played, app.statusMessage)
+	}
+	if got := fake.Loaded(); len(got) != 1 {
+		t.Errorf("Expected the queue not to advance, loaded %v", got)
+	}
+	if len(app.subscriptions.Queue) != 1 || app.subscriptions.GetQueuePosition(episodes[1].ID) != 1 {
+		t.Errorf("Expected only the next episode left in the queue, got %d entries", len(app.subscriptions.Queue))
+	}
+}
+
+func TestApp_SleepTimerEndOfChapter(t *testing.T) {
+	app, fake := newTestApp(t)
+	episodes := addTestPodcast(app, fake, 2, 10*time.Minute)
+	fake.SetChapters(episodes[0].URL, []player.Chapter{{Title: "Intro"}, {Title: "News", Start: 2 * time.Minute}})
+
+	app.playEpisode(episodes[1])
+	app.setSleepTimer([]string{"chapter"})
+	if app.statusMessage != "This episode has no chapters" {
+		t.Errorf("Expected episodes without chapters to be refused, got %q", app.statusMessage)
+	}
+
+	app.playEpisode(episodes[0])
+	settle(app, fake)
+	app.setSleepTimer([]string{"chapter"})
+	fake.Advance(time.Minute)
+	app.tickSleepTimer()
+	if !fake.IsPlaying() {
+		t.Fatal("Expected playback to go on until the chapter ends")
+	}
+	fake.Advance(time.Minute)
+	app.tickSleepTimer()
+	if !fake.IsPaused() {
+		t.Errorf("Expected playback paused at the end of the chapter, got %v", fake.GetState())
+	}
+}
+
+func TestApp_SleepTimerKeys(t *testing.T) {
+	app, _ := newTestApp(t)
+
+	app.extendSleepTimer()
+	app.extendSleepTimer()
+	if app.sleep.mode != sleepAfter || app.sleep.left != 2*sleepStep {
+		t.Errorf("Expected z twice to set 30 minutes, got %v", app.sleep.left)
+	}
+
+	app.cancelSleepTimer()
+	if app.sleep.mode != sleepOff || app.statusMessage != "Sleep timer cancelled" {
+		t.Errorf("Expected the timer cancelled, status %q", app.statusMessage)
+	}
+
+	app.setSleepTimer([]string{"soon"})
+	if !strings.HasPrefix(app.statusMessage, "Usage") || app.sleep.mode != sleepOff {
+		t.Errorf("Expected a usage message, got %q", app.statusMessage)
+	}
+	app.setSleepTimer([]string{"1h30m"})
+	if app.sleep.left != 90*time.Minute {
+		t.Errorf("Expected a 1h30m timer, got %v", app.sleep.left)
+	}
+}
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"log"
+
+	"github.com/csams/podcast-tui/internal/credentials"
+)
+
+// untrackedURL returns the URL an enclosure is fetched from. In privacy mode known
+// tracking prefixes are removed, so analytics services never see the request.
+func (a *App) untrackedURL(url string) string {
+	if a.settings == nil || !a.settings.PrivacyMode || a.trackers == nil {
+		return url
+	}
+	untracked := a.trackers.Unwrap(url)
+	if untracked != url {
+		log.Printf("Privacy mode: removed tracking prefixes from %s", credentials.RedactURL(untracked))
+	}
+	return untracked
+}